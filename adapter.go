@@ -0,0 +1,368 @@
+package solenodon
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// NodeAdapter teaches a Container how to traverse and modify data of a particular type, see RegisterAdapter.
+// Methods that modify data return the modified data. Data that can be modified in place, such as a map,
+// is returned as is. Otherwise a modified copy is returned, which replaces the data in its parent.
+type NodeAdapter interface {
+	// Child returns the value at the given key in data, or false if there is none.
+	Child(data, key interface{}) (interface{}, bool)
+	// SetChild replaces the value at the given key, which must be present, in data.
+	// It returns false if the value cannot be set.
+	SetChild(data, key, value interface{}) (interface{}, bool)
+	// DeleteChild removes the value at the given key from data.
+	// It returns false if there is no such value, or if it cannot be removed.
+	DeleteChild(data, key interface{}) (interface{}, bool)
+	// Keys returns the keys of the children of data, in a deterministic order.
+	Keys(data interface{}) []interface{}
+}
+
+// ChildAdder can be implemented by a NodeAdapter to allow Set to add new children.
+type ChildAdder interface {
+	// AddChild adds the value at the given key, which must not yet be present, to data.
+	// It returns false if the value cannot be added.
+	AddChild(data, key, value interface{}) (interface{}, bool)
+}
+
+// CloneAdapter can be implemented by a NodeAdapter to deep copy its data, which is shared by Clone otherwise.
+type CloneAdapter interface {
+	// CloneNode returns a copy of data that shares nothing that the adapter modifies.
+	// The given function deep copies the children of data.
+	CloneNode(data interface{}, clone func(interface{}) interface{}) interface{}
+}
+
+// Shape describes how the children of data are organized.
+type Shape int
+
+const (
+	// ShapeScalar means that the data has no children.
+	ShapeScalar Shape = iota
+	// ShapeMap means that the data maps keys to children.
+	ShapeMap
+	// ShapeSequence means that the keys of the children are the ints 0 up to the number of children.
+	ShapeSequence
+)
+
+// ShapeAdapter can be implemented by a NodeAdapter to report the shape of data, which determines how operations such
+// as Merge, Equal and Query treat it. Data of an adapter without it is treated as a map.
+type ShapeAdapter interface {
+	Shape(data interface{}) Shape
+}
+
+// childLookup can be implemented by a NodeAdapter to report why Child found no value.
+// Otherwise ReasonKeyNotFound is reported.
+type childLookup interface {
+	lookupChild(data, key interface{}) (interface{}, Reason)
+}
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = map[reflect.Type]NodeAdapter{
+		reflect.TypeOf(map[string]interface{}{}):      stringMapAdapter{},
+		reflect.TypeOf(map[interface{}]interface{}{}): interfaceMapAdapter{},
+		reflect.TypeOf([]interface{}{}):               sliceAdapter{},
+		reflect.TypeOf([]map[string]interface{}{}):    mapSliceAdapter{},
+	}
+)
+
+// RegisterAdapter sets the NodeAdapter that is used for data of the given type, replacing any built-in one.
+// A nil adapter removes the registration.
+func RegisterAdapter(t reflect.Type, adapter NodeAdapter) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	if adapter == nil {
+		delete(adapters, t)
+		return
+	}
+	adapters[t] = adapter
+}
+
+// adapterOf returns the NodeAdapter for the given data.
+func adapterOf(data interface{}) NodeAdapter {
+	adaptersMu.RLock()
+	adapter, ok := adapters[reflect.TypeOf(data)]
+	adaptersMu.RUnlock()
+	if ok {
+		return adapter
+	}
+	return reflectAdapter{}
+}
+
+// shapeOf returns the shape of the given data, as reported by its NodeAdapter.
+func shapeOf(data interface{}) Shape {
+	if shaper, ok := adapterOf(data).(ShapeAdapter); ok {
+		return shaper.Shape(data)
+	}
+	return ShapeMap
+}
+
+// sameNode reports whether b refers to the same map, slice or pointer as a,
+// i.e. whether a NodeAdapter modified a in place instead of returning a copy.
+func sameNode(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() || va.Type() != vb.Type() {
+		return false
+	}
+	switch va.Kind() {
+	case reflect.Map, reflect.Ptr:
+		return va.Pointer() == vb.Pointer()
+	case reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	}
+	return false
+}
+
+type stringMapAdapter struct{}
+
+func (stringMapAdapter) Shape(interface{}) Shape {
+	return ShapeMap
+}
+
+func (a stringMapAdapter) Child(data, key interface{}) (interface{}, bool) {
+	value, reason := a.lookupChild(data, key)
+	return value, reason == 0
+}
+
+func (stringMapAdapter) lookupChild(data, key interface{}) (interface{}, Reason) {
+	k, ok := key.(string)
+	if !ok {
+		return nil, ReasonInvalidKey
+	}
+	value, ok := data.(map[string]interface{})[k]
+	if !ok {
+		return nil, ReasonKeyNotFound
+	}
+	return value, 0
+}
+
+func (stringMapAdapter) SetChild(data, key, value interface{}) (interface{}, bool) {
+	w := data.(map[string]interface{})
+	k, ok := key.(string)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := w[k]; !ok {
+		return nil, false
+	}
+	w[k] = value
+	return w, true
+}
+
+func (stringMapAdapter) AddChild(data, key, value interface{}) (interface{}, bool) {
+	w := data.(map[string]interface{})
+	k, ok := key.(string)
+	if !ok {
+		return nil, false
+	}
+	w[k] = value
+	return w, true
+}
+
+func (stringMapAdapter) DeleteChild(data, key interface{}) (interface{}, bool) {
+	w := data.(map[string]interface{})
+	k, ok := key.(string)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := w[k]; !ok {
+		return nil, false
+	}
+	delete(w, k)
+	return w, true
+}
+
+func (stringMapAdapter) Keys(data interface{}) []interface{} {
+	w := data.(map[string]interface{})
+	keys := make([]interface{}, 0, len(w))
+	for k := range w {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].(string) < keys[j].(string) })
+	return keys
+}
+
+type interfaceMapAdapter struct{}
+
+func (interfaceMapAdapter) Shape(interface{}) Shape {
+	return ShapeMap
+}
+
+func (a interfaceMapAdapter) Child(data, key interface{}) (interface{}, bool) {
+	value, reason := a.lookupChild(data, key)
+	return value, reason == 0
+}
+
+func (interfaceMapAdapter) lookupChild(data, key interface{}) (interface{}, Reason) {
+	if t := reflect.TypeOf(key); t != nil && !t.Comparable() {
+		return nil, ReasonInvalidKey
+	}
+	value, ok := data.(map[interface{}]interface{})[key]
+	if !ok {
+		return nil, ReasonKeyNotFound
+	}
+	return value, 0
+}
+
+func (interfaceMapAdapter) SetChild(data, key, value interface{}) (interface{}, bool) {
+	w := data.(map[interface{}]interface{})
+	if _, ok := w[key]; !ok {
+		return nil, false
+	}
+	w[key] = value
+	return w, true
+}
+
+func (interfaceMapAdapter) AddChild(data, key, value interface{}) (interface{}, bool) {
+	w := data.(map[interface{}]interface{})
+	w[key] = value
+	return w, true
+}
+
+func (interfaceMapAdapter) DeleteChild(data, key interface{}) (interface{}, bool) {
+	w := data.(map[interface{}]interface{})
+	if _, ok := w[key]; !ok {
+		return nil, false
+	}
+	delete(w, key)
+	return w, true
+}
+
+func (interfaceMapAdapter) Keys(data interface{}) []interface{} {
+	w := data.(map[interface{}]interface{})
+	keys := make([]interface{}, 0, len(w))
+	for k := range w {
+		keys = append(keys, k)
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return lessKey(keys[i], keys[j]) })
+	return keys
+}
+
+type sliceAdapter struct{}
+
+func (sliceAdapter) Shape(interface{}) Shape {
+	return ShapeSequence
+}
+
+func (a sliceAdapter) Child(data, key interface{}) (interface{}, bool) {
+	value, reason := a.lookupChild(data, key)
+	return value, reason == 0
+}
+
+func (sliceAdapter) lookupChild(data, key interface{}) (interface{}, Reason) {
+	w := data.([]interface{})
+	i, ok := key.(int)
+	if !ok {
+		return nil, ReasonInvalidKey
+	}
+	if i < 0 || i >= len(w) {
+		return nil, ReasonIndexOutOfRange
+	}
+	return w[i], 0
+}
+
+func (sliceAdapter) SetChild(data, key, value interface{}) (interface{}, bool) {
+	w := data.([]interface{})
+	i, ok := key.(int)
+	if !ok || i < 0 || i >= len(w) {
+		return nil, false
+	}
+	w[i] = value
+	return w, true
+}
+
+func (sliceAdapter) AddChild(data, key, value interface{}) (interface{}, bool) {
+	return growSlice(data.([]interface{}), key, value)
+}
+
+func (sliceAdapter) DeleteChild(data, key interface{}) (interface{}, bool) {
+	w := data.([]interface{})
+	i, ok := key.(int)
+	if !ok || i < 0 || i >= len(w) {
+		return nil, false
+	}
+	slice := make([]interface{}, 0, len(w)-1)
+	return append(append(slice, w[:i]...), w[i+1:]...), true
+}
+
+func (sliceAdapter) Keys(data interface{}) []interface{} {
+	return indexKeys(len(data.([]interface{})))
+}
+
+// mapSliceAdapter handles the []map[string]interface{} that github.com/BurntSushi/toml produces for arrays of tables.
+// Values that are not a map[string]interface{} turn it into a []interface{}.
+type mapSliceAdapter struct{}
+
+func (mapSliceAdapter) Shape(interface{}) Shape {
+	return ShapeSequence
+}
+
+func (a mapSliceAdapter) Child(data, key interface{}) (interface{}, bool) {
+	value, reason := a.lookupChild(data, key)
+	return value, reason == 0
+}
+
+func (mapSliceAdapter) lookupChild(data, key interface{}) (interface{}, Reason) {
+	w := data.([]map[string]interface{})
+	i, ok := key.(int)
+	if !ok {
+		return nil, ReasonInvalidKey
+	}
+	if i < 0 || i >= len(w) {
+		return nil, ReasonIndexOutOfRange
+	}
+	return w[i], 0
+}
+
+func (mapSliceAdapter) SetChild(data, key, value interface{}) (interface{}, bool) {
+	w := data.([]map[string]interface{})
+	i, ok := key.(int)
+	if !ok || i < 0 || i >= len(w) {
+		return nil, false
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		w[i] = m
+		return w, true
+	}
+	slice := interfaceSlice(w)
+	slice[i] = value
+	return slice, true
+}
+
+func (mapSliceAdapter) AddChild(data, key, value interface{}) (interface{}, bool) {
+	return growSlice(interfaceSlice(data.([]map[string]interface{})), key, value)
+}
+
+func (mapSliceAdapter) DeleteChild(data, key interface{}) (interface{}, bool) {
+	w := data.([]map[string]interface{})
+	i, ok := key.(int)
+	if !ok || i < 0 || i >= len(w) {
+		return nil, false
+	}
+	slice := make([]map[string]interface{}, 0, len(w)-1)
+	return append(append(slice, w[:i]...), w[i+1:]...), true
+}
+
+func (mapSliceAdapter) Keys(data interface{}) []interface{} {
+	return indexKeys(len(data.([]map[string]interface{})))
+}
+
+// MaxSliceGrowth is the maximum number of nil values with which Set fills a slice to reach the given index.
+// It protects against untrusted indices, e.g. from Unflatten or ApplyEnv, that would allocate huge slices.
+const MaxSliceGrowth = 1024
+
+// growSlice returns a copy of the slice that is grown with nil values to hold the value at the given index.
+func growSlice(w []interface{}, key, value interface{}) (interface{}, bool) {
+	i, ok := key.(int)
+	if !ok || i < len(w) || i-len(w) > MaxSliceGrowth {
+		return nil, false
+	}
+	slice := make([]interface{}, i+1)
+	copy(slice, w)
+	slice[i] = value
+	return slice, true
+}
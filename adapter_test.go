@@ -0,0 +1,235 @@
+package solenodon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+type orderedMapAdapter struct{}
+
+func (orderedMapAdapter) Child(data, key interface{}) (interface{}, bool) {
+	k, _ := key.(string)
+	value, ok := data.(*orderedMap).values[k]
+	return value, ok
+}
+
+func (a orderedMapAdapter) SetChild(data, key, value interface{}) (interface{}, bool) {
+	if _, ok := a.Child(data, key); !ok {
+		return nil, false
+	}
+	data.(*orderedMap).values[key.(string)] = value
+	return data, true
+}
+
+func (orderedMapAdapter) AddChild(data, key, value interface{}) (interface{}, bool) {
+	k, ok := key.(string)
+	if !ok {
+		return nil, false
+	}
+	m := data.(*orderedMap)
+	m.keys = append(m.keys, k)
+	m.values[k] = value
+	return m, true
+}
+
+func (a orderedMapAdapter) DeleteChild(data, key interface{}) (interface{}, bool) {
+	if _, ok := a.Child(data, key); !ok {
+		return nil, false
+	}
+	m := data.(*orderedMap)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	delete(m.values, key.(string))
+	return m, true
+}
+
+func (orderedMapAdapter) Keys(data interface{}) []interface{} {
+	var keys []interface{}
+	for _, k := range data.(*orderedMap).keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// cloneableOrderedMapAdapter is an orderedMapAdapter that implements CloneAdapter.
+type cloneableOrderedMapAdapter struct {
+	orderedMapAdapter
+}
+
+func (cloneableOrderedMapAdapter) CloneNode(data interface{}, clone func(interface{}) interface{}) interface{} {
+	m := data.(*orderedMap)
+	c := &orderedMap{keys: append([]string(nil), m.keys...), values: map[string]interface{}{}}
+	for k, v := range m.values {
+		c.values[k] = clone(v)
+	}
+	return c
+}
+
+func TestRegisterAdapter(t *testing.T) {
+	RegisterAdapter(reflect.TypeOf(&orderedMap{}), orderedMapAdapter{})
+	defer RegisterAdapter(reflect.TypeOf(&orderedMap{}), nil)
+
+	m := &orderedMap{keys: []string{"z", "a"}, values: map[string]interface{}{"z": 1, "a": []interface{}{2}}}
+	container := NewContainer(map[string]interface{}{"m": m})
+	if data := container.Get("m", "a", 0).Data(); data != 2 {
+		t.Errorf("expected 2, got %v", data)
+	}
+	if keys := container.Get("m").Keys(); !reflect.DeepEqual(keys, []interface{}{"z", "a"}) {
+		t.Errorf("expected keys in insertion order, got %v", keys)
+	}
+	if container.Get("m", "z").SetData(3) == nil || m.values["z"] != 3 {
+		t.Errorf("expected z to be set, got %v", m.values["z"])
+	}
+	if container.Get("m", "a", 0).SetData(4) == nil || !reflect.DeepEqual(m.values["a"], []interface{}{4}) {
+		t.Errorf("expected nested value to be set, got %v", m.values["a"])
+	}
+	if container.Set("new", "m", "b", "c") == nil {
+		t.Fatal("expected Set to add a child")
+	}
+	if !reflect.DeepEqual(m.keys, []string{"z", "a", "b"}) || !reflect.DeepEqual(m.values["b"], map[string]interface{}{"c": "new"}) {
+		t.Errorf("unexpected ordered map %v %v", m.keys, m.values)
+	}
+	container.Delete("m", "z")
+	if !reflect.DeepEqual(m.keys, []string{"a", "b"}) {
+		t.Errorf("expected z to be deleted, got %v", m.keys)
+	}
+	if _, err := container.Lookup("m", "missing"); err == nil || err.(*PathError).Reason != ReasonKeyNotFound {
+		t.Errorf("expected key not found, got %v", err)
+	}
+}
+
+func TestRegisterAdapterNil(t *testing.T) {
+	type strings []string
+	RegisterAdapter(reflect.TypeOf(strings{}), orderedMapAdapter{})
+	RegisterAdapter(reflect.TypeOf(strings{}), nil)
+	// Without an adapter, the slice is traversed with reflection again.
+	if data := NewContainer(strings{"a", "b"}).Get(1).Data(); data != "b" {
+		t.Errorf("expected b, got %v", data)
+	}
+}
+
+func TestAdapterOperations(t *testing.T) {
+	newContainer := func() *Container {
+		return NewContainer(map[string]interface{}{
+			"tags":  []string{"a", "b"},
+			"ports": map[string]int{"http": 80},
+		})
+	}
+	container := newContainer()
+	if data := container.GetPointer("/tags/1").Data(); data != "b" {
+		t.Errorf("expected b from pointer, got %v", data)
+	}
+	for _, test := range []struct {
+		expr string
+		n    int
+	}{
+		{"$.tags[0]", 1},
+		{"$.tags[-1]", 1},
+		{"$.tags[0:2]", 2},
+		{"$.ports.http", 1},
+		{"$.tags[?@ == 'b']", 1},
+	} {
+		results, err := container.Query(test.expr)
+		if err != nil || len(results) != test.n {
+			t.Errorf("%s, expected %d results, got %d (%v)", test.expr, test.n, len(results), err)
+		}
+	}
+	decoded := NewContainer(map[string]interface{}{
+		"tags":  []interface{}{"a", "b"},
+		"ports": map[string]interface{}{"http": 80.0},
+	})
+	if !container.Equal(decoded) {
+		t.Error("expected Go values to equal decoded values")
+	}
+	if changes := Diff(container, decoded); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+
+	if container.Get("tags").Insert(1, "x") == nil {
+		t.Fatal("expected Insert into []string to succeed")
+	}
+	if data := container.Get("tags").Data(); !reflect.DeepEqual(data, []string{"a", "x", "b"}) {
+		t.Errorf("unexpected tags %v", data)
+	}
+	err := container.ApplyPatch([]PatchOp{{Op: "add", Path: "/tags/-", Value: "c"}, {Op: "replace", Path: "/ports/http", Value: 8080}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := container.Data(); !reflect.DeepEqual(data, map[string]interface{}{
+		"tags":  []string{"a", "x", "b", "c"},
+		"ports": map[string]int{"http": 8080},
+	}) {
+		t.Errorf("unexpected patched data %v", data)
+	}
+
+	container = newContainer()
+	err = container.Merge(NewContainer(map[string]interface{}{"tags": []interface{}{"c"}, "ports": map[string]interface{}{"https": 443}}),
+		WithArrayStrategy(ArrayAppend))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := container.Data(); !reflect.DeepEqual(data, map[string]interface{}{
+		"tags":  []string{"a", "b", "c"},
+		"ports": map[string]int{"http": 80, "https": 443},
+	}) {
+		t.Errorf("unexpected merged data %v", data)
+	}
+	container.Set([]int{}, "ids")
+	if err := container.ApplyEnv("APP_", WithEnviron([]string{"APP_IDS=1,2"})); err != nil {
+		t.Fatal(err)
+	}
+	if data := container.Get("ids").Data(); !reflect.DeepEqual(data, []int{1, 2}) {
+		t.Errorf("expected ids parsed into []int, got %v (%T)", data, data)
+	}
+}
+
+func TestAdapterShape(t *testing.T) {
+	RegisterAdapter(reflect.TypeOf(&orderedMap{}), orderedMapAdapter{})
+	defer RegisterAdapter(reflect.TypeOf(&orderedMap{}), nil)
+
+	m := &orderedMap{keys: []string{"z", "a"}, values: map[string]interface{}{"z": 1, "a": 2}}
+	if !NewContainer(m).Equal(NewContainer(map[string]interface{}{"a": 2, "z": 1})) {
+		t.Error("expected data of an adapter without ShapeAdapter to be compared as a map")
+	}
+	if flat := NewContainer(m).Flatten("."); !reflect.DeepEqual(flat, map[string]interface{}{"z": 1, "a": 2}) {
+		t.Errorf("unexpected flattened data %v", flat)
+	}
+	if shapeOf(time.Time{}) != ShapeScalar || shapeOf([]byte("x")) != ShapeScalar {
+		t.Error("expected time.Time and []byte to be scalars")
+	}
+}
+
+func TestAdapterApplyPatch(t *testing.T) {
+	ops := []PatchOp{{Op: "remove", Path: "/m/x"}, {Op: "test", Path: "/nope", Value: 1}}
+	for i, adapter := range []NodeAdapter{orderedMapAdapter{}, cloneableOrderedMapAdapter{}} {
+		RegisterAdapter(reflect.TypeOf(&orderedMap{}), adapter)
+		m := &orderedMap{keys: []string{"x"}, values: map[string]interface{}{"x": 1}}
+		container := NewContainer(map[string]interface{}{"m": m})
+		if err := container.ApplyPatch(ops); err == nil {
+			t.Errorf("%d, expected error", i)
+		}
+		if !reflect.DeepEqual(m.keys, []string{"x"}) || m.values["x"] != 1 {
+			t.Errorf("%d, expected data to be unchanged, got %v %v", i, m.keys, m.values)
+		}
+	}
+	defer RegisterAdapter(reflect.TypeOf(&orderedMap{}), nil)
+
+	m := &orderedMap{keys: []string{"x"}, values: map[string]interface{}{"x": 1}}
+	container := NewContainer(map[string]interface{}{"m": m})
+	if err := container.ApplyPatch(ops[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if keys := container.Get("m").Keys(); len(keys) != 0 || len(m.keys) != 1 {
+		t.Errorf("expected x to be removed from a copy, got %v and original %v", keys, m.keys)
+	}
+}
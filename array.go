@@ -0,0 +1,101 @@
+package solenodon
+
+import "reflect"
+
+// Append appends the given values to the slice in the Container, which may hold nil.
+// The Container on which this method is called will be returned, or nil if it does not hold a slice.
+func (c *Container) Append(values ...interface{}) *Container {
+	length, _ := arrayLength(c.Data())
+	return c.Insert(length, values...)
+}
+
+// Prepend inserts the given values at the start of the slice in the Container, which may hold nil.
+// The Container on which this method is called will be returned, or nil if it does not hold a slice.
+func (c *Container) Prepend(values ...interface{}) *Container {
+	return c.Insert(0, values...)
+}
+
+// Insert inserts the given values into the slice in the Container, before the element at the given index.
+// The index is an int, or an Index that counts from the end, e.g. Index(-1) inserts before the last element.
+// An index equal to the length appends the values. A new slice is written back through the parent Container.
+// The Container on which this method is called will be returned, or nil if it does not hold a slice.
+func (c *Container) Insert(index interface{}, values ...interface{}) *Container {
+	if c == nil {
+		return c
+	}
+	length, ok := arrayLength(c.data)
+	if !ok && c.data != nil {
+		return nil
+	}
+	var i int
+	switch v := index.(type) {
+	case int:
+		i = v
+	case Index:
+		i = int(v)
+		if i < 0 {
+			i += length
+		}
+	default:
+		return nil
+	}
+	if i < 0 || i > length {
+		return nil
+	}
+	elements, _ := sliceElements(c.data)
+	slice, ok := sequenceLike(c.data, insertValues(elements, i, values))
+	if !ok {
+		return nil
+	}
+	if c.SetData(slice) == nil {
+		return nil
+	}
+	return c
+}
+
+func insertValues(w []interface{}, index int, values []interface{}) []interface{} {
+	slice := make([]interface{}, 0, len(w)+len(values))
+	slice = append(slice, w[:index]...)
+	slice = append(slice, values...)
+	return append(slice, w[index:]...)
+}
+
+// sequenceLike returns the elements as a slice of the same type as the given slice, if they fit.
+// A []map[string]interface{} becomes a []interface{} if not all elements are a map[string]interface{}.
+func sequenceLike(like interface{}, elements []interface{}) (interface{}, bool) {
+	switch like.(type) {
+	case nil, []interface{}:
+		return elements, true
+	case []map[string]interface{}:
+		if maps, ok := stringMaps(elements); ok {
+			return maps, true
+		}
+		return elements, true
+	}
+	v, ok := reflectValue(elements, reflect.TypeOf(like))
+	if !ok {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// stringMaps returns the values as a slice of map[string]interface{}, if they all are of that type.
+func stringMaps(values []interface{}) ([]map[string]interface{}, bool) {
+	maps := make([]map[string]interface{}, len(values))
+	for i, value := range values {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		maps[i] = m
+	}
+	return maps, true
+}
+
+func interfaceSlice(w []map[string]interface{}) []interface{} {
+	slice := make([]interface{}, len(w))
+	for i, x := range w {
+		slice[i] = x
+	}
+	return slice
+}
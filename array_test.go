@@ -0,0 +1,92 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestAppendInsertPrependInJSON(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(`{"items":[2,3]}`), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := container.Get("items")
+	if items.Append(4.0, 5.0) != items {
+		t.Fatal("expected Append to return the container")
+	}
+	if items.Prepend(1.0) != items {
+		t.Fatal("expected Prepend to return the container")
+	}
+	if items.Insert(2, 2.5) != items {
+		t.Fatal("expected Insert to return the container")
+	}
+	expected := []interface{}{1.0, 2.0, 2.5, 3.0, 4.0, 5.0}
+	if !reflect.DeepEqual(container.Get("items").Data(), expected) {
+		t.Errorf("expected %v, got %v", expected, container.Get("items").Data())
+	}
+}
+
+func TestInsertDoesNotAlias(t *testing.T) {
+	original := make([]interface{}, 2, 10)
+	original[0], original[1] = 1, 2
+	container := NewContainer(map[string]interface{}{"items": original})
+	container.Get("items").Insert(1, 3)
+	if !reflect.DeepEqual(original, []interface{}{1, 2}) || original[:3][2] != nil {
+		t.Errorf("expected original slice to be untouched, got %v", original[:3])
+	}
+}
+
+func TestDeleteDoesNotAlias(t *testing.T) {
+	original := []interface{}{1, 2, 3}
+	container := NewContainer(map[string]interface{}{"items": original})
+	container.Delete("items", 0)
+	if !reflect.DeepEqual(original, []interface{}{1, 2, 3}) {
+		t.Errorf("expected original slice to be untouched, got %v", original)
+	}
+}
+
+func TestAppendInTOML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	friends := container.Get("friends")
+	friends.Append(map[string]interface{}{"id": int64(3)})
+	if _, ok := container.Get("friends").Data().([]map[string]interface{}); !ok {
+		t.Errorf("expected []map[string]interface{}, got %T", container.Get("friends").Data())
+	}
+	friends.Append("foo")
+	if data := container.Get("friends", 4).Data(); data != "foo" {
+		t.Errorf("expected foo, got %v", data)
+	}
+	if data := container.Get("friends", 3, "id").Data(); data != int64(3) {
+		t.Errorf("expected 3, got %v", data)
+	}
+}
+
+func TestAppendToNil(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"items": nil})
+	container.Get("items").Append(1)
+	if !reflect.DeepEqual(container.Get("items").Data(), []interface{}{1}) {
+		t.Errorf("unexpected data %v", container.Get("items").Data())
+	}
+}
+
+func TestInsertInvalid(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"items": []interface{}{1}, "foo": "bar"})
+	if container.Get("items").Insert(2, 1) != nil {
+		t.Error("expected nil when inserting out of range")
+	}
+	if container.Get("items").Insert(-1, 1) != nil {
+		t.Error("expected nil when inserting at negative index")
+	}
+	if container.Get("foo").Append(1) != nil {
+		t.Error("expected nil when appending to a string")
+	}
+	if container.Get("missing").Append(1) != nil {
+		t.Error("expected nil when appending to nil container")
+	}
+}
@@ -0,0 +1,37 @@
+package solenodon
+
+// Keys returns the keys of the children of the Container, or nil if it has none.
+// Map keys are sorted, and keys of mixed types are ordered by kind first: nil, booleans, numbers and strings.
+// Struct fields are in the order in which they are declared.
+func (c *Container) Keys() []interface{} {
+	return childKeys(c.Data())
+}
+
+// Len returns the number of children of the Container, i.e. the length of Keys.
+func (c *Container) Len() int {
+	switch w := c.Data().(type) {
+	case map[string]interface{}:
+		return len(w)
+	case map[interface{}]interface{}:
+		return len(w)
+	case []interface{}:
+		return len(w)
+	case []map[string]interface{}:
+		return len(w)
+	}
+	return len(c.Keys())
+}
+
+// Children returns a Container for each child of the Container, in the order of Keys.
+// Each child is linked to this Container, so SetData on a child modifies the original data.
+func (c *Container) Children() []*Container {
+	keys := c.Keys()
+	if keys == nil {
+		return nil
+	}
+	children := make([]*Container, len(keys))
+	for i, key := range keys {
+		children[i] = c.Get(key)
+	}
+	return children
+}
@@ -0,0 +1,70 @@
+package solenodon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func TestKeysOfMixedYAMLMap(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte("b: 1\n10: 2\na: 3\n2: 4\ntrue: 5\n~: 6\n1.5: 7\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{nil, true, 1.5, 2, 10, "a", "b"}
+	for i := 0; i < 10; i++ {
+		if keys := container.Keys(); !reflect.DeepEqual(keys, expected) {
+			t.Fatalf("expected %v, got %v", expected, keys)
+		}
+	}
+	if container.Len() != 7 {
+		t.Errorf("expected length 7, got %d", container.Len())
+	}
+}
+
+func TestKeysOfEqualNumbers(t *testing.T) {
+	container := NewContainer(map[interface{}]interface{}{1: "a", int64(1): "b", 1.0: "c", uint8(1): "d", 0.5: "e"})
+	expected := []interface{}{0.5, 1.0, 1, int64(1), uint8(1)}
+	for i := 0; i < 10; i++ {
+		if keys := container.Keys(); !reflect.DeepEqual(keys, expected) {
+			t.Fatalf("expected %v, got %v", expected, keys)
+		}
+	}
+}
+
+func TestChildren(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	friends := container.Get("friends")
+	if !reflect.DeepEqual(friends.Keys(), []interface{}{0, 1, 2}) {
+		t.Errorf("unexpected keys %v", friends.Keys())
+	}
+	children := friends.Children()
+	if len(children) != friends.Len() {
+		t.Fatalf("expected %d children, got %d", friends.Len(), len(children))
+	}
+	for _, child := range children {
+		if child.Parent() != friends {
+			t.Error("expected child to be linked to its parent")
+		}
+		child.Get("name").SetData("Big Bob")
+	}
+	if container.Get("friends", 2, "name").Data() != "Big Bob" {
+		t.Error("expected SetData on a child to modify the original data")
+	}
+}
+
+func TestChildrenOfLeaf(t *testing.T) {
+	container := NewContainer("foo")
+	if container.Keys() != nil || container.Children() != nil || container.Len() != 0 {
+		t.Error("expected a leaf to have no children")
+	}
+	var nilContainer *Container
+	if nilContainer.Keys() != nil || nilContainer.Children() != nil || nilContainer.Len() != 0 {
+		t.Error("expected a nil container to have no children")
+	}
+}
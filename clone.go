@@ -0,0 +1,140 @@
+package solenodon
+
+import "reflect"
+
+// Clone returns a new root Container holding a deep copy of the data in this Container.
+// Data with a registered NodeAdapter is shared, unless the adapter implements CloneAdapter.
+func (c *Container) Clone() *Container {
+	if c == nil {
+		return c
+	}
+	return NewContainer(cloneData(c.data))
+}
+
+func cloneData(data interface{}) interface{} {
+	return (&cloner{}).clone(data)
+}
+
+// cloneDetached deep copies data like cloneData, but fails if any of it has to be shared.
+func cloneDetached(data interface{}) (interface{}, bool) {
+	cl := &cloner{}
+	data = cl.clone(data)
+	return data, !cl.shared
+}
+
+// cloner deep copies data. It remembers the pointers it has copied, so that cyclic Go values can be copied.
+type cloner struct {
+	pointers map[pointerKey]reflect.Value
+	// shared is set when data of an adapter without CloneAdapter is shared instead of copied.
+	shared bool
+}
+
+type pointerKey struct {
+	t reflect.Type
+	p uintptr
+}
+
+func (cl *cloner) clone(data interface{}) interface{} {
+	switch w := data.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(w))
+		for k, v := range w {
+			m[k] = cl.clone(v)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(w))
+		for k, v := range w {
+			m[k] = cl.clone(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(w))
+		for i, v := range w {
+			s[i] = cl.clone(v)
+		}
+		return s
+	case []map[string]interface{}:
+		s := make([]map[string]interface{}, len(w))
+		for i, v := range w {
+			s[i] = cl.clone(v).(map[string]interface{})
+		}
+		return s
+	case []byte:
+		return append([]byte(nil), w...)
+	}
+	switch adapter := adapterOf(data).(type) {
+	case reflectAdapter:
+	case CloneAdapter:
+		return adapter.CloneNode(data, cl.clone)
+	default:
+		cl.shared = true
+		return data
+	}
+	return cl.value(reflect.ValueOf(data)).Interface()
+}
+
+// value deep copies the maps, slices, arrays, exported struct fields and pointers that reflectAdapter can modify.
+func (cl *cloner) value(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || !v.CanInterface() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(reflect.ValueOf(cl.clone(v.Elem().Interface())))
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), cl.value(iter.Value()))
+		}
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(cl.value(v.Index(i)))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			a.Index(i).Set(cl.value(v.Index(i)))
+		}
+		return a
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		s.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if s.Field(i).CanSet() {
+				s.Field(i).Set(cl.value(v.Field(i)))
+			}
+		}
+		return s
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := pointerKey{t: v.Type(), p: v.Pointer()}
+		if p, ok := cl.pointers[key]; ok {
+			return p
+		}
+		if cl.pointers == nil {
+			cl.pointers = map[pointerKey]reflect.Value{}
+		}
+		p := reflect.New(v.Type().Elem())
+		cl.pointers[key] = p
+		p.Elem().Set(cl.value(v.Elem()))
+		return p
+	}
+	return v
+}
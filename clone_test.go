@@ -0,0 +1,109 @@
+package solenodon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCloneTOML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := container.Get("owner").Parent().Clone()
+	if clone.Parent() != nil {
+		t.Error("expected clone to be a root container")
+	}
+	if !reflect.DeepEqual(clone.Data(), container.Data()) {
+		t.Fatal("expected clone to equal the original")
+	}
+	if _, ok := clone.Get("owner", "time").Data().(time.Time); !ok {
+		t.Errorf("expected time.Time, got %T", clone.Get("owner", "time").Data())
+	}
+	clone.Get("friends", 0, "name").SetData("Big Bob")
+	clone.Get("database", "ports", 0).SetData(int64(1))
+	clone.Delete("owner", "name")
+	if container.Get("friends", 0, "name").Data() != "Wood Compton" {
+		t.Error("expected original friends to be untouched")
+	}
+	if container.Get("database", "ports", 0).Data() != int64(8080) {
+		t.Error("expected original ports to be untouched")
+	}
+	if !container.Has("owner", "name") {
+		t.Error("expected original owner to be untouched")
+	}
+}
+
+func TestCloneYAML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte("1: [a, {b: c}]\nbytes: !!binary aGVsbG8=\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := container.Clone()
+	if !reflect.DeepEqual(clone.Data(), container.Data()) {
+		t.Fatal("expected clone to equal the original")
+	}
+	clone.Get(1, 1, "b").SetData("d")
+	if container.Get(1, 1, "b").Data() != "c" {
+		t.Error("expected original to be untouched")
+	}
+}
+
+func TestCloneNilContainer(t *testing.T) {
+	var container *Container
+	if container.Clone() != nil {
+		t.Error("expected clone of nil container to be nil")
+	}
+}
+
+func TestCloneGoValues(t *testing.T) {
+	type node struct {
+		Name string         `json:"name"`
+		Tags map[string]int `json:"tags"`
+		Next *node          `json:"next"`
+		Sub  [1][]string    `json:"sub"`
+		Any  interface{}    `json:"any"`
+	}
+	cyclic := &node{Name: "cycle"}
+	cyclic.Next = cyclic
+	orig := NewContainer(map[string]interface{}{
+		"tags":   []string{"a", "b"},
+		"counts": map[string]int{"a": 1},
+		"node":   &node{Tags: map[string]int{"x": 1}, Sub: [1][]string{{"s"}}, Any: []int{1}},
+		"value":  node{Name: "v", Tags: map[string]int{"y": 1}},
+		"cyclic": cyclic,
+	})
+	clone := orig.Clone()
+	clone.Get("tags", 0).SetData("X")
+	clone.Get("counts", "a").SetData(2)
+	clone.Get("node", "tags", "x").SetData(2)
+	clone.Get("node", "sub", 0, 0).SetData("t")
+	clone.Get("node", "any", 0).SetData(2)
+	clone.Get("value", "tags", "y").SetData(2)
+	clone.Get("cyclic", "name").SetData("changed")
+
+	expected := []struct {
+		keys     []interface{}
+		expected interface{}
+	}{
+		{[]interface{}{"tags", 0}, "a"},
+		{[]interface{}{"counts", "a"}, 1},
+		{[]interface{}{"node", "tags", "x"}, 1},
+		{[]interface{}{"node", "sub", 0, 0}, "s"},
+		{[]interface{}{"node", "any", 0}, 1},
+		{[]interface{}{"value", "tags", "y"}, 1},
+		{[]interface{}{"cyclic", "next", "name"}, "cycle"},
+	}
+	for i, test := range expected {
+		if data := orig.Get(test.keys...).Data(); data != test.expected {
+			t.Errorf("%d, expected original %v, got %v", i, test.expected, data)
+		}
+	}
+	if data := clone.Get("cyclic", "next", "next", "name").Data(); data != "changed" {
+		t.Errorf("expected cycle to be preserved in the clone, got %v", data)
+	}
+}
@@ -0,0 +1,265 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ErrNotFound is returned by the typed accessors when they are called on a nil Container,
+// i.e. when Get did not find a value.
+var ErrNotFound = errors.New("solenodon: value not found")
+
+// ConversionError is returned by the typed accessors when the data in a Container cannot be converted to the requested type.
+type ConversionError struct {
+	// Data is the data that could not be converted.
+	Data interface{}
+	// Type is the name of the requested type.
+	Type string
+	// Lossy is true if the data has a convertible type, but its value cannot be represented exactly,
+	// e.g. 3.5 as an int.
+	Lossy bool
+}
+
+func (e *ConversionError) Error() string {
+	if e.Lossy {
+		return fmt.Sprintf("solenodon: cannot convert %v (%T) to %s without loss", e.Data, e.Data, e.Type)
+	}
+	return fmt.Sprintf("solenodon: cannot convert %v (%T) to %s", e.Data, e.Data, e.Type)
+}
+
+// String returns the data in the Container as a string.
+// Both string and []byte data are accepted.
+func (c *Container) String() (string, error) {
+	if c == nil {
+		return "", ErrNotFound
+	}
+	switch v := c.data.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	}
+	return "", &ConversionError{Data: c.data, Type: "string"}
+}
+
+// StringOr returns the data in the Container as a string, or the given default if that is not possible.
+func (c *Container) StringOr(def string) string {
+	if v, err := c.String(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Int returns the data in the Container as an int.
+// Any integer or floating point type is accepted, as long as the value can be represented exactly.
+func (c *Container) Int() (int, error) {
+	if c == nil {
+		return 0, ErrNotFound
+	}
+	v, err := toInt64(c.data, "int")
+	if err != nil {
+		return 0, err
+	}
+	if v < math.MinInt || v > math.MaxInt {
+		return 0, &ConversionError{Data: c.data, Type: "int", Lossy: true}
+	}
+	return int(v), nil
+}
+
+// IntOr returns the data in the Container as an int, or the given default if that is not possible.
+func (c *Container) IntOr(def int) int {
+	if v, err := c.Int(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Int64 returns the data in the Container as an int64.
+// Any integer or floating point type is accepted, as long as the value can be represented exactly.
+func (c *Container) Int64() (int64, error) {
+	if c == nil {
+		return 0, ErrNotFound
+	}
+	return toInt64(c.data, "int64")
+}
+
+// Int64Or returns the data in the Container as an int64, or the given default if that is not possible.
+func (c *Container) Int64Or(def int64) int64 {
+	if v, err := c.Int64(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Float64 returns the data in the Container as a float64.
+// Any integer or floating point type is accepted, as long as the value can be represented exactly.
+func (c *Container) Float64() (float64, error) {
+	if c == nil {
+		return 0, ErrNotFound
+	}
+	switch v := c.data.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, &ConversionError{Data: c.data, Type: "float64"}
+		}
+		return f, nil
+	}
+	if i, ok := c.data.(uint64); ok {
+		f := float64(i)
+		if f >= 1<<64 || uint64(f) != i {
+			return 0, &ConversionError{Data: c.data, Type: "float64", Lossy: true}
+		}
+		return f, nil
+	}
+	i, err := toInt64(c.data, "float64")
+	if err != nil {
+		return 0, err
+	}
+	f := float64(i)
+	if f >= 1<<63 || int64(f) != i {
+		return 0, &ConversionError{Data: c.data, Type: "float64", Lossy: true}
+	}
+	return f, nil
+}
+
+// Float64Or returns the data in the Container as a float64, or the given default if that is not possible.
+func (c *Container) Float64Or(def float64) float64 {
+	if v, err := c.Float64(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Bool returns the data in the Container as a bool.
+func (c *Container) Bool() (bool, error) {
+	if c == nil {
+		return false, ErrNotFound
+	}
+	if v, ok := c.data.(bool); ok {
+		return v, nil
+	}
+	return false, &ConversionError{Data: c.data, Type: "bool"}
+}
+
+// BoolOr returns the data in the Container as a bool, or the given default if that is not possible.
+func (c *Container) BoolOr(def bool) bool {
+	if v, err := c.Bool(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Time returns the data in the Container as a time.Time. Strings are parsed as RFC 3339.
+func (c *Container) Time() (time.Time, error) {
+	if c == nil {
+		return time.Time{}, ErrNotFound
+	}
+	switch v := c.data.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, &ConversionError{Data: c.data, Type: "time.Time"}
+}
+
+// TimeOr returns the data in the Container as a time.Time, or the given default if that is not possible.
+func (c *Container) TimeOr(def time.Time) time.Time {
+	if v, err := c.Time(); err == nil {
+		return v
+	}
+	return def
+}
+
+// Duration returns the data in the Container as a time.Duration. Strings are parsed with time.ParseDuration.
+// Integers are nanoseconds, so e.g. the TOML value timeout = 30 is 30ns.
+func (c *Container) Duration() (time.Duration, error) {
+	if c == nil {
+		return 0, ErrNotFound
+	}
+	switch v := c.data.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, &ConversionError{Data: c.data, Type: "time.Duration"}
+		}
+		return d, nil
+	}
+	i, err := toInt64(c.data, "time.Duration")
+	return time.Duration(i), err
+}
+
+// DurationOr returns the data in the Container as a time.Duration, or the given default if that is not possible.
+func (c *Container) DurationOr(def time.Duration) time.Duration {
+	if v, err := c.Duration(); err == nil {
+		return v
+	}
+	return def
+}
+
+// toInt64 converts any of the numeric types produced by the supported decoders to an int64.
+// typeName is used in the returned error.
+func toInt64(data interface{}, typeName string) (int64, error) {
+	switch v := data.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return uintToInt64(uint64(v), data, typeName)
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return uintToInt64(v, data, typeName)
+	case float32:
+		return floatToInt64(float64(v), data, typeName)
+	case float64:
+		return floatToInt64(v, data, typeName)
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return i, nil
+		}
+		if f, err := v.Float64(); err == nil {
+			return floatToInt64(f, data, typeName)
+		}
+	}
+	return 0, &ConversionError{Data: data, Type: typeName}
+}
+
+func uintToInt64(v uint64, data interface{}, typeName string) (int64, error) {
+	if v > math.MaxInt64 {
+		return 0, &ConversionError{Data: data, Type: typeName, Lossy: true}
+	}
+	return int64(v), nil
+}
+
+func floatToInt64(v float64, data interface{}, typeName string) (int64, error) {
+	if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, &ConversionError{Data: data, Type: typeName, Lossy: true}
+	}
+	return int64(v), nil
+}
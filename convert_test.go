@@ -0,0 +1,130 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNumericAccessorsAcrossDecoders(t *testing.T) {
+	unmarshals := map[string]unmarshal{
+		"json": json.Unmarshal,
+		"toml": toml.Unmarshal,
+		"yaml": yaml.Unmarshal,
+	}
+	raws := map[string]string{
+		"json": rawJSON,
+		"toml": rawTOML,
+		"yaml": rawYAML,
+	}
+	for name, unmarshal := range unmarshals {
+		container, err := NewContainerFromBytes([]byte(raws[name]), unmarshal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		port := container.Get("database", "ports", 2)
+		if v, err := port.Int(); err != nil || v != 8081 {
+			t.Errorf("%s, expected int 8081, got %v (%v)", name, v, err)
+		}
+		if v, err := port.Int64(); err != nil || v != 8081 {
+			t.Errorf("%s, expected int64 8081, got %v (%v)", name, v, err)
+		}
+		if v, err := port.Float64(); err != nil || v != 8081 {
+			t.Errorf("%s, expected float64 8081, got %v (%v)", name, v, err)
+		}
+		threshold := container.Get("database", "threshold")
+		if v, err := threshold.Float64(); err != nil || v != 30.5 {
+			t.Errorf("%s, expected float64 30.5, got %v (%v)", name, v, err)
+		}
+		var conversionErr *ConversionError
+		if _, err := threshold.Int(); !errors.As(err, &conversionErr) || !conversionErr.Lossy {
+			t.Errorf("%s, expected lossy conversion error, got %v", name, err)
+		}
+		if v, err := container.Get("database", "enabled").Bool(); err != nil || !v {
+			t.Errorf("%s, expected true, got %v (%v)", name, v, err)
+		}
+		if v, err := container.Get("title").String(); err != nil || v != "example" {
+			t.Errorf("%s, expected example, got %v (%v)", name, v, err)
+		}
+		expectedTime := time.Date(2001, 2, 20, 21, 3, 55, 0, time.UTC)
+		if v, err := container.Get("owner", "time").Time(); err != nil || !v.Equal(expectedTime) {
+			t.Errorf("%s, expected %v, got %v (%v)", name, expectedTime, v, err)
+		}
+	}
+}
+
+func TestConversionErrors(t *testing.T) {
+	tests := []struct {
+		data  interface{}
+		lossy bool
+		get   func(c *Container) error
+	}{
+		{data: "foo", get: func(c *Container) error { _, err := c.Int(); return err }},
+		{data: 3.5, lossy: true, get: func(c *Container) error { _, err := c.Int64(); return err }},
+		{data: math.Inf(1), lossy: true, get: func(c *Container) error { _, err := c.Int64(); return err }},
+		{data: uint64(math.MaxUint64), lossy: true, get: func(c *Container) error { _, err := c.Int64(); return err }},
+		{data: int64(1<<53 + 1), lossy: true, get: func(c *Container) error { _, err := c.Float64(); return err }},
+		{data: 1, get: func(c *Container) error { _, err := c.String(); return err }},
+		{data: "true", get: func(c *Container) error { _, err := c.Bool(); return err }},
+		{data: "yesterday", get: func(c *Container) error { _, err := c.Time(); return err }},
+		{data: "soon", get: func(c *Container) error { _, err := c.Duration(); return err }},
+	}
+	for i, test := range tests {
+		err := test.get(NewContainer(test.data))
+		var conversionErr *ConversionError
+		if !errors.As(err, &conversionErr) {
+			t.Errorf("%d, expected conversion error, got %v", i, err)
+		} else if conversionErr.Lossy != test.lossy {
+			t.Errorf("%d, expected lossy %v, got %v", i, test.lossy, conversionErr.Lossy)
+		}
+	}
+}
+
+func TestAccessorsOnNilContainer(t *testing.T) {
+	var container *Container
+	if _, err := container.Int(); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if container.IntOr(3) != 3 {
+		t.Error("expected default int")
+	}
+	if container.StringOr("foo") != "foo" {
+		t.Error("expected default string")
+	}
+}
+
+func TestOtherConversions(t *testing.T) {
+	if v, err := NewContainer("1m30s").Duration(); err != nil || v != 90*time.Second {
+		t.Errorf("expected 1m30s, got %v (%v)", v, err)
+	}
+	if v := NewContainer(int64(5)).DurationOr(0); v != 5 {
+		t.Errorf("expected 5ns, got %v", v)
+	}
+	if v, err := NewContainer(json.Number("12")).Int(); err != nil || v != 12 {
+		t.Errorf("expected 12, got %v (%v)", v, err)
+	}
+	if v, err := NewContainer(uint8(7)).Float64(); err != nil || v != 7 {
+		t.Errorf("expected 7, got %v (%v)", v, err)
+	}
+	if v := NewContainer([]byte("foo")).StringOr(""); v != "foo" {
+		t.Errorf("expected foo, got %v", v)
+	}
+	if v := NewContainer(1.0).Float64Or(2); v != 1 {
+		t.Errorf("expected 1, got %v", v)
+	}
+	if v := NewContainer(1.0).Int64Or(2); v != 1 {
+		t.Errorf("expected 1, got %v", v)
+	}
+	if v := NewContainer(nil).BoolOr(true); !v {
+		t.Errorf("expected true, got %v", v)
+	}
+	def := time.Unix(0, 0)
+	if v := NewContainer(nil).TimeOr(def); !v.Equal(def) {
+		t.Errorf("expected %v, got %v", def, v)
+	}
+}
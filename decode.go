@@ -0,0 +1,407 @@
+package solenodon
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeError is returned by Decode when a value cannot be decoded into the target.
+type DecodeError struct {
+	// Path contains the keys, relative to the decoded Container, of the value that could not be decoded.
+	Path []interface{}
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("solenodon: cannot decode %q: %s", FormatPath(e.Path...), e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeOption configures Decode.
+type DecodeOption func(*decoder)
+
+// WithTagName sets the name of the struct tag that is used to map keys to struct fields,
+// e.g. "json", "yaml" or "toml". The default is "json".
+func WithTagName(name string) DecodeOption {
+	return func(d *decoder) {
+		d.tagName = name
+	}
+}
+
+// Decode stores the data in the Container in the value pointed to by target.
+// Struct fields match map keys by tag or field name, preferring an exact match; the tag "-" skips a field.
+// Embedded structs are inlined unless their tag has a name and no "inline" option.
+// Numbers are converted as by Int64 and Float64, and strings are decoded into encoding.TextUnmarshaler types.
+// Go values that are assignable to the target are copied as by Clone.
+func (c *Container) Decode(target interface{}, opts ...DecodeOption) error {
+	if c == nil {
+		return ErrNotFound
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("solenodon: decode target must be a non-nil pointer, got %T", target)
+	}
+	d := &decoder{tagName: "json"}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d.decode(c.data, v.Elem(), nil)
+}
+
+type decoder struct {
+	tagName string
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func (d *decoder) decode(data interface{}, v reflect.Value, path []interface{}) error {
+	fail := func(err error) error {
+		var decodeErr *DecodeError
+		if errors.As(err, &decodeErr) {
+			return err
+		}
+		return &DecodeError{Path: append([]interface{}{}, path...), Err: err}
+	}
+	if data == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() != reflect.Interface && reflect.TypeOf(data).AssignableTo(v.Type()) {
+		v.Set(reflect.ValueOf(cloneData(data)))
+		return nil
+	}
+	if s, ok := data.(string); ok && v.Kind() != reflect.Ptr && reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return fail(err)
+		}
+		return nil
+	}
+	switch v.Type() {
+	case timeType:
+		t, err := NewContainer(data).Time()
+		if err != nil {
+			return fail(err)
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		duration, err := NewContainer(data).Duration()
+		if err != nil {
+			return fail(err)
+		}
+		v.SetInt(int64(duration))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(data, v.Elem(), path)
+	case reflect.Interface:
+		value := reflect.ValueOf(data)
+		if !value.Type().AssignableTo(v.Type()) {
+			return fail(fmt.Errorf("cannot assign %T to %s", data, v.Type()))
+		}
+		v.Set(value)
+	case reflect.Bool:
+		b, err := NewContainer(data).Bool()
+		if err != nil {
+			return fail(err)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		s, err := NewContainer(data).String()
+		if err != nil {
+			return fail(err)
+		}
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(data, v.Type().String())
+		if err != nil {
+			return fail(err)
+		}
+		if v.OverflowInt(i) {
+			return fail(&ConversionError{Data: data, Type: v.Type().String(), Lossy: true})
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		if x, ok := data.(uint64); ok {
+			u = x
+		} else {
+			i, err := toInt64(data, v.Type().String())
+			if err != nil {
+				return fail(err)
+			}
+			if i < 0 {
+				return fail(&ConversionError{Data: data, Type: v.Type().String(), Lossy: true})
+			}
+			u = uint64(i)
+		}
+		if v.OverflowUint(u) {
+			return fail(&ConversionError{Data: data, Type: v.Type().String(), Lossy: true})
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := NewContainer(data).Float64()
+		if err != nil {
+			return fail(err)
+		}
+		if v.OverflowFloat(f) {
+			return fail(&ConversionError{Data: data, Type: v.Type().String(), Lossy: true})
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if s, ok := data.(string); ok && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(s))
+			return nil
+		}
+		elements, ok := sliceElements(data)
+		if !ok {
+			return fail(fmt.Errorf("cannot decode %T into %s", data, v.Type()))
+		}
+		slice := reflect.MakeSlice(v.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			if err := d.decode(element, slice.Index(i), append(path, i)); err != nil {
+				return fail(err)
+			}
+		}
+		v.Set(slice)
+	case reflect.Array:
+		elements, ok := sliceElements(data)
+		if !ok {
+			return fail(fmt.Errorf("cannot decode %T into %s", data, v.Type()))
+		}
+		if len(elements) > v.Len() {
+			return fail(fmt.Errorf("cannot decode %d elements into %s", len(elements), v.Type()))
+		}
+		for i := 0; i < v.Len(); i++ {
+			var element interface{}
+			if i < len(elements) {
+				element = elements[i]
+			}
+			if err := d.decode(element, v.Index(i), append(path, i)); err != nil {
+				return fail(err)
+			}
+		}
+	case reflect.Map:
+		entries, ok := mapEntries(data)
+		if !ok {
+			return fail(fmt.Errorf("cannot decode %T into %s", data, v.Type()))
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(entries))
+		for _, entry := range entries {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := d.decodeKey(entry.key, key); err != nil {
+				return fail(&DecodeError{Path: append(append([]interface{}{}, path...), entry.key), Err: err})
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := d.decode(entry.value, value, append(path, entry.key)); err != nil {
+				return fail(err)
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	case reflect.Struct:
+		entries, ok := mapEntries(data)
+		if !ok {
+			return fail(fmt.Errorf("cannot decode %T into %s", data, v.Type()))
+		}
+		return d.decodeStruct(entries, v, path)
+	default:
+		return fail(fmt.Errorf("cannot decode into %s", v.Type()))
+	}
+	return nil
+}
+
+// decodeKey decodes a map key. Strings are parsed when decoded into a numeric or boolean key.
+func (d *decoder) decodeKey(data interface{}, key reflect.Value) error {
+	if s, ok := data.(string); ok {
+		switch key.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(s, 10, key.Type().Bits())
+			if err != nil {
+				return err
+			}
+			key.SetInt(i)
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u, err := strconv.ParseUint(s, 10, key.Type().Bits())
+			if err != nil {
+				return err
+			}
+			key.SetUint(u)
+			return nil
+		case reflect.Bool:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			key.SetBool(b)
+			return nil
+		}
+	} else if key.Kind() == reflect.String {
+		key.SetString(fmt.Sprint(data))
+		return nil
+	}
+	return d.decode(data, key, nil)
+}
+
+func (d *decoder) decodeStruct(entries []mapEntry, v reflect.Value, path []interface{}) error {
+	for _, field := range d.structFields(v.Type(), nil) {
+		entry, ok := findEntry(entries, field.name)
+		if !ok {
+			continue
+		}
+		fieldValue, err := fieldByIndex(v, field.index)
+		if err != nil {
+			return &DecodeError{Path: append(append([]interface{}{}, path...), entry.key), Err: err}
+		}
+		if err := d.decode(entry.value, fieldValue, append(path, entry.key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFields returns the exported fields of the given struct type, including those of embedded structs.
+// Fields of the outer struct take precedence over fields of embedded structs with the same name.
+func (d *decoder) structFields(t reflect.Type, index []int) []structField {
+	var fields, embedded []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(d.tagName)
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fieldIndex := append(append([]int{}, index...), i)
+		if f.Anonymous {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			inline := name == "" || strings.Contains(","+options+",", ",inline,")
+			if ft.Kind() == reflect.Struct && inline {
+				embedded = append(embedded, d.structFields(ft, fieldIndex)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, structField{name: name, index: fieldIndex})
+	}
+	for _, e := range embedded {
+		shadowed := false
+		for _, f := range fields {
+			if f.name == e.name {
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			fields = append(fields, e)
+		}
+	}
+	return fields
+}
+
+// fieldByIndex returns the nested field of v, allocating nil embedded struct pointers along the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+type mapEntry struct {
+	key, value interface{}
+}
+
+// mapEntries returns the entries of a map, in the order of its keys.
+func mapEntries(data interface{}) ([]mapEntry, bool) {
+	if !isMap(data) {
+		return nil, false
+	}
+	keys := childKeys(data)
+	entries := make([]mapEntry, 0, len(keys))
+	for _, key := range keys {
+		if value, reason := child(data, key); reason == 0 {
+			entries = append(entries, mapEntry{key: key, value: value})
+		}
+	}
+	return entries, true
+}
+
+// findEntry returns the entry whose key matches the given name, exactly or else case-insensitively.
+func findEntry(entries []mapEntry, name string) (mapEntry, bool) {
+	for _, entry := range entries {
+		if entry.key == name {
+			return entry, true
+		}
+	}
+	for _, entry := range entries {
+		if s, ok := entry.key.(string); ok && strings.EqualFold(s, name) {
+			return entry, true
+		}
+	}
+	return mapEntry{}, false
+}
+
+// sliceElements returns the elements of a sequence. A []interface{} is returned as is.
+func sliceElements(data interface{}) ([]interface{}, bool) {
+	if w, ok := data.([]interface{}); ok {
+		if _, ok := adapterOf(data).(sliceAdapter); ok {
+			return w, true
+		}
+	}
+	if !isSlice(data) {
+		return nil, false
+	}
+	keys := childKeys(data)
+	elements := make([]interface{}, len(keys))
+	for i, key := range keys {
+		elements[i], _ = child(data, key)
+	}
+	return elements, true
+}
+
+// arrayLength returns the number of elements of a sequence.
+func arrayLength(data interface{}) (int, bool) {
+	if !isSlice(data) {
+		return 0, false
+	}
+	return len(childKeys(data)), true
+}
@@ -0,0 +1,200 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type decodeDatabase struct {
+	Server    net.IP  `json:"server" yaml:"server" toml:"server"`
+	Ports     []int   `json:"ports" yaml:"ports" toml:"ports"`
+	Threshold float32 `json:"threshold" yaml:"threshold" toml:"threshold"`
+	Enabled   *bool   `json:"enabled" yaml:"enabled" toml:"enabled"`
+}
+
+type decodeOwner struct {
+	Name string    `json:"name" yaml:"name" toml:"name"`
+	Time time.Time `json:"time" yaml:"time" toml:"time"`
+}
+
+type decodeConfig struct {
+	Title    string                       `json:"title" yaml:"title" toml:"title"`
+	Owner    decodeOwner                  `json:"owner" yaml:"owner" toml:"owner"`
+	Database decodeDatabase               `json:"database" yaml:"database" toml:"database"`
+	Servers  map[string]map[string]string `json:"servers" yaml:"servers" toml:"servers"`
+	Hosts    [2]string                    `json:"hosts" yaml:"hosts" toml:"hosts"`
+	Ignored  string                       `json:"-" yaml:"-" toml:"-"`
+}
+
+func TestDecodeAcrossDecoders(t *testing.T) {
+	tests := []struct {
+		tagName   string
+		raw       string
+		unmarshal unmarshal
+	}{
+		{tagName: "json", raw: rawJSON, unmarshal: json.Unmarshal},
+		{tagName: "yaml", raw: rawYAML, unmarshal: yaml.Unmarshal},
+		{tagName: "toml", raw: rawTOML, unmarshal: toml.Unmarshal},
+	}
+	enabled := true
+	expected := decodeConfig{
+		Title: "example",
+		Owner: decodeOwner{Name: "macabot", Time: time.Date(2001, 2, 20, 21, 3, 55, 0, time.UTC)},
+		Database: decodeDatabase{
+			Server:    net.ParseIP("127.0.0.1"),
+			Ports:     []int{8080, 8080, 8081},
+			Threshold: 30.5,
+			Enabled:   &enabled,
+		},
+		Servers: map[string]map[string]string{
+			"alpha": {"ip": "10.0.0.1"},
+			"beta":  {"ip": "10.0.0.2", "log": "false"},
+		},
+		Hosts: [2]string{"alpha", "omega"},
+	}
+	for i, test := range tests {
+		container, err := NewContainerFromBytes([]byte(test.raw), test.unmarshal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		container.Get("servers", "beta", "log").SetData("false")
+		var config decodeConfig
+		if err := container.Decode(&config, WithTagName(test.tagName)); err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		if !config.Owner.Time.Equal(expected.Owner.Time) {
+			t.Errorf("%d, expected time %v, got %v", i, expected.Owner.Time, config.Owner.Time)
+		}
+		config.Owner.Time = expected.Owner.Time
+		if !reflect.DeepEqual(config, expected) {
+			t.Errorf("%d, expected %+v, got %+v", i, expected, config)
+		}
+	}
+}
+
+func TestDecodeSubtreeFromTOML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var friends []struct {
+		ID   uint8
+		Name string
+	}
+	if err := container.Get("friends").Decode(&friends, WithTagName("toml")); err != nil {
+		t.Fatal(err)
+	}
+	if len(friends) != 3 || friends[2].ID != 2 || friends[2].Name != "Catalina Newton" {
+		t.Errorf("unexpected friends %+v", friends)
+	}
+}
+
+func TestDecodeEmbeddedAndKeys(t *testing.T) {
+	type Base struct {
+		ID int `yaml:"id"`
+	}
+	type Item struct {
+		Base   `yaml:",inline"`
+		Labels map[int]string `yaml:"labels"`
+		Data   interface{}    `yaml:"data"`
+	}
+	raw := "id: 4\nlabels:\n  1: one\n  \"2\": two\ndata: [1, b]\n"
+	container, err := NewContainerFromBytes([]byte(raw), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var item Item
+	if err := container.Decode(&item, WithTagName("yaml")); err != nil {
+		t.Fatal(err)
+	}
+	expected := Item{
+		Base:   Base{ID: 4},
+		Labels: map[int]string{1: "one", 2: "two"},
+		Data:   []interface{}{1, "b"},
+	}
+	if !reflect.DeepEqual(item, expected) {
+		t.Errorf("expected %+v, got %+v", expected, item)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	container := NewContainer(map[string]interface{}{
+		"items": []interface{}{1.0, 2.5},
+		"big":   300.0,
+	})
+	var target struct {
+		Items []int `json:"items"`
+	}
+	err := container.Decode(&target)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if !reflect.DeepEqual(decodeErr.Path, []interface{}{"items", 1}) {
+		t.Errorf("unexpected path %v", decodeErr.Path)
+	}
+	var small struct {
+		Big int8 `json:"big"`
+	}
+	if err := container.Decode(&small); err == nil {
+		t.Error("expected overflow error")
+	}
+	if err := container.Decode(target); err == nil {
+		t.Error("expected error for non-pointer target")
+	}
+	var nilContainer *Container
+	if err := nilContainer.Decode(&target); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDecodeGoValues(t *testing.T) {
+	type inner struct {
+		A      int `json:"a"`
+		hidden string
+	}
+	var target struct {
+		Tags   []string       `json:"tags"`
+		Counts map[string]int `json:"counts"`
+		Inner  inner          `json:"inner"`
+		Ports  []int          `json:"ports"`
+		Server struct {
+			Host string `json:"host"`
+		} `json:"server"`
+	}
+	tags := []string{"a"}
+	container := NewContainer(map[string]interface{}{
+		"tags":   tags,
+		"counts": map[string]int{"x": 1},
+		"inner":  inner{A: 3, hidden: "h"},
+		"ports":  [2]float64{80, 443},
+		"server": struct {
+			Host string `json:"host"`
+			Port int    `json:"port"`
+		}{Host: "alpha", Port: 80},
+	})
+	if err := container.Decode(&target); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(target.Tags, []string{"a"}) || !reflect.DeepEqual(target.Counts, map[string]int{"x": 1}) {
+		t.Errorf("unexpected tags %v or counts %v", target.Tags, target.Counts)
+	}
+	if target.Inner != (inner{A: 3, hidden: "h"}) {
+		t.Errorf("expected assignable struct to be copied, got %+v", target.Inner)
+	}
+	if !reflect.DeepEqual(target.Ports, []int{80, 443}) || target.Server.Host != "alpha" {
+		t.Errorf("unexpected ports %v or server %+v", target.Ports, target.Server)
+	}
+	target.Tags[0] = "changed"
+	if tags[0] != "a" {
+		t.Error("expected decoded slice not to alias the data")
+	}
+}
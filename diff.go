@@ -0,0 +1,186 @@
+package solenodon
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChangeKind describes how a value changed.
+type ChangeKind int
+
+const (
+	// ChangeAdded means that the value is only present in the new data.
+	ChangeAdded ChangeKind = iota + 1
+	// ChangeRemoved means that the value is only present in the old data.
+	ChangeRemoved
+	// ChangeModified means that the value has the same kind in both, e.g. both are strings, but is not equal.
+	ChangeModified
+	// ChangeTypeChanged means that the value has a different kind, e.g. a map became a string.
+	ChangeTypeChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	case ChangeTypeChanged:
+		return "type changed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a difference between two Containers, as returned by Diff.
+type Change struct {
+	// Path contains the keys of the changed value, as accepted by Get.
+	Path []interface{}
+	Kind ChangeKind
+	// Old is the value in the old data, or nil if the value was added.
+	Old interface{}
+	// New is the value in the new data, or nil if the value was removed.
+	New interface{}
+}
+
+// Diff returns the changes that turn the data of a into the data of b, in an order in which they can be applied.
+// Maps are compared by key regardless of their flavor, slices by index, and numbers by value.
+// A nil Container is treated as missing data.
+func Diff(a, b *Container) []Change {
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		return []Change{{Path: []interface{}{}, Kind: ChangeAdded, New: b.data}}
+	case b == nil:
+		return []Change{{Path: []interface{}{}, Kind: ChangeRemoved, Old: a.data}}
+	}
+	return (&differ{}).diff(nil, []interface{}{}, a.data, b.data)
+}
+
+// differ compares data. It remembers the pointers and maps it is comparing, so that cyclic Go values can be compared.
+type differ struct {
+	comparing pointerPairs
+}
+
+func (d *differ) diff(changes []Change, path []interface{}, a, b interface{}) []Change {
+	childPath := func(key interface{}) []interface{} {
+		return append(append(make([]interface{}, 0, len(path)+1), path...), key)
+	}
+	leave, ok := d.comparing.enter(a, b)
+	if !ok {
+		return changes
+	}
+	defer leave()
+	if isMap(a) && isMap(b) {
+		for _, key := range childKeys(a) {
+			if _, reason := child(b, key); reason != 0 {
+				old, _ := child(a, key)
+				changes = append(changes, Change{Path: childPath(key), Kind: ChangeRemoved, Old: old})
+			}
+		}
+		for _, key := range childKeys(b) {
+			value, _ := child(b, key)
+			if old, reason := child(a, key); reason == 0 {
+				changes = d.diff(changes, childPath(key), old, value)
+			} else {
+				changes = append(changes, Change{Path: childPath(key), Kind: ChangeAdded, New: value})
+			}
+		}
+		return changes
+	}
+	if isSlice(a) && isSlice(b) {
+		elementsA, _ := sliceElements(a)
+		elementsB, _ := sliceElements(b)
+		for i := 0; i < len(elementsA) && i < len(elementsB); i++ {
+			changes = d.diff(changes, childPath(i), elementsA[i], elementsB[i])
+		}
+		for i := len(elementsA) - 1; i >= len(elementsB); i-- {
+			changes = append(changes, Change{Path: childPath(i), Kind: ChangeRemoved, Old: elementsA[i]})
+		}
+		for i := len(elementsA); i < len(elementsB); i++ {
+			changes = append(changes, Change{Path: childPath(i), Kind: ChangeAdded, New: elementsB[i]})
+		}
+		return changes
+	}
+	if valuesEqual(a, b) {
+		return changes
+	}
+	kind := ChangeModified
+	if valueKind(a) != valueKind(b) {
+		kind = ChangeTypeChanged
+	}
+	return append(changes, Change{Path: path, Kind: kind, Old: a, New: b})
+}
+
+// ChangesToPatch converts the given changes, as returned by Diff, into JSON Patch operations.
+// Added values result in "add", removed values in "remove" and other changes in "replace" operations.
+func ChangesToPatch(changes []Change) []PatchOp {
+	ops := make([]PatchOp, len(changes))
+	for i, change := range changes {
+		op := PatchOp{Path: FormatPointer(change.Path...)}
+		switch change.Kind {
+		case ChangeAdded:
+			op.Op = "add"
+			op.Value = change.New
+		case ChangeRemoved:
+			op.Op = "remove"
+		default:
+			op.Op = "replace"
+			op.Value = change.New
+		}
+		ops[i] = op
+	}
+	return ops
+}
+
+type kind int
+
+const (
+	kindNil kind = iota
+	kindBool
+	kindNumber
+	kindString
+	kindTime
+	kindMap
+	kindSlice
+	kindOther
+)
+
+// valueKind returns the kind of the given value, ignoring the differences in representation between decoders.
+func valueKind(data interface{}) kind {
+	switch data.(type) {
+	case nil:
+		return kindNil
+	case bool:
+		return kindBool
+	case string:
+		return kindString
+	case time.Time:
+		return kindTime
+	}
+	if _, ok := toFloat64(data); ok {
+		return kindNumber
+	}
+	if isMap(data) {
+		return kindMap
+	}
+	if isSlice(data) {
+		return kindSlice
+	}
+	return kindOther
+}
+
+// numbersEqual compares two numbers of any type by value.
+// Integers are compared exactly, so that large int64 values that map to the same float64 are not equal.
+func numbersEqual(a, b interface{}) bool {
+	if x, err := toInt64(a, ""); err == nil {
+		if y, err := toInt64(b, ""); err == nil {
+			return x == y
+		}
+	}
+	x, _ := toFloat64(a)
+	y, _ := toFloat64(b)
+	return x == y
+}
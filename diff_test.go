@@ -0,0 +1,112 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDiff(t *testing.T) {
+	a := newJSONContainer(t, `{"a":1,"b":{"c":"x","d":[1,2,3]},"e":true,"f":{"g":1}}`)
+	b := newJSONContainer(t, `{"a":2,"b":{"c":"y","d":[1,5]},"e":"true","h":null,"f":{"g":1}}`)
+	expected := []Change{
+		{Path: []interface{}{"a"}, Kind: ChangeModified, Old: 1.0, New: 2.0},
+		{Path: []interface{}{"b", "c"}, Kind: ChangeModified, Old: "x", New: "y"},
+		{Path: []interface{}{"b", "d", 1}, Kind: ChangeModified, Old: 2.0, New: 5.0},
+		{Path: []interface{}{"b", "d", 2}, Kind: ChangeRemoved, Old: 3.0},
+		{Path: []interface{}{"e"}, Kind: ChangeTypeChanged, Old: true, New: "true"},
+		{Path: []interface{}{"h"}, Kind: ChangeAdded, New: nil},
+	}
+	changes := Diff(a, b)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %v, got %v", expected, changes)
+	}
+}
+
+func TestDiffAcrossDecoders(t *testing.T) {
+	fromTOML, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := NewContainerFromBytes([]byte(rawYAML), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := NewContainerFromBytes([]byte(rawJSON), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The raw YAML has no friends.
+	fromTOML.Delete("friends")
+	if changes := Diff(fromTOML, fromYAML); len(changes) != 0 {
+		t.Errorf("expected no changes between TOML and YAML, got %v", changes)
+	}
+	// JSON has no time type.
+	fromYAML.Delete("owner", "time")
+	fromJSON.Delete("owner", "time")
+	if changes := Diff(fromYAML, fromJSON); len(changes) != 0 {
+		t.Errorf("expected no changes between YAML and JSON, got %v", changes)
+	}
+}
+
+func TestDiffNilContainers(t *testing.T) {
+	container := NewContainer(1)
+	if changes := Diff(nil, container); len(changes) != 1 || changes[0].Kind != ChangeAdded {
+		t.Errorf("expected one added change, got %v", changes)
+	}
+	if changes := Diff(container, nil); len(changes) != 1 || changes[0].Kind != ChangeRemoved {
+		t.Errorf("expected one removed change, got %v", changes)
+	}
+	if changes := Diff(nil, nil); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestChangesToPatch(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{a: `{"a":1,"b":{"c":"x","d":[1,2,3]},"e":true}`, b: `{"a":2,"b":{"c":"y","d":[1]},"e":"true","f/g":[1]}`},
+		{a: `{"items":[1,2,3,4]}`, b: `{"items":[]}`},
+		{a: `{"items":[]}`, b: `{"items":[1,{"a":2}]}`},
+		{a: `[1,2]`, b: `{"a":1}`},
+	}
+	for i, test := range tests {
+		a := newJSONContainer(t, test.a)
+		b := newJSONContainer(t, test.b)
+		if err := a.ApplyPatch(ChangesToPatch(Diff(a, b))); err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(a.Data(), b.Data()) {
+			t.Errorf("%d, expected %v, got %v", i, b.Data(), a.Data())
+		}
+	}
+}
+
+func TestChangesToPatchJSONNull(t *testing.T) {
+	a := newJSONContainer(t, `{"a":1,"b":2}`)
+	b := newJSONContainer(t, `{"a":null,"h":null}`)
+	raw, err := json.Marshal(ChangesToPatch(Diff(a, b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"op":"remove","path":"/b"},{"op":"replace","path":"/a","value":null},` +
+		`{"op":"add","path":"/h","value":null}]`
+	if string(raw) != expected {
+		t.Errorf("expected %s, got %s", expected, raw)
+	}
+	var ops []PatchOp
+	if err := json.Unmarshal(raw, &ops); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.ApplyPatch(ops); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(b) {
+		t.Errorf("expected %v, got %v", b.Data(), a.Data())
+	}
+}
@@ -0,0 +1,200 @@
+package solenodon
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvOption configures ApplyEnv.
+type EnvOption func(*envLoader)
+
+// WithEnviron sets the environment variables, in the "key=value" form of os.Environ, that ApplyEnv uses
+// instead of those of the current process.
+func WithEnviron(environ []string) EnvOption {
+	return func(l *envLoader) {
+		l.environ = environ
+	}
+}
+
+// WithEnvSeparator sets the separator between the segments of the name of an environment variable.
+// The default is "__", so that single underscores can be part of a key, e.g. APP_LOG_LEVEL refers to "log_level".
+func WithEnvSeparator(sep string) EnvOption {
+	return func(l *envLoader) {
+		l.sep = sep
+	}
+}
+
+type envLoader struct {
+	environ []string
+	sep     string
+}
+
+// ApplyEnv sets the environment variables whose name starts with the given prefix in the Container,
+// e.g. with the prefix "APP_", APP_DATABASE__SERVER is set at the path "database", "server".
+// Segments match map keys case-insensitively, new keys are lowercased, and numeric segments are slice indices.
+// Values are parsed into the type of the existing value, and slices from a comma-separated list.
+// Variables are applied in sorted order, and the first one that cannot be applied is returned as an error.
+func (c *Container) ApplyEnv(prefix string, opts ...EnvOption) error {
+	if c == nil {
+		return ErrNotFound
+	}
+	l := &envLoader{sep: "__"}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.environ == nil {
+		l.environ = os.Environ()
+	}
+	environ := append([]string{}, l.environ...)
+	sort.Strings(environ)
+	for _, variable := range environ {
+		name, raw, ok := strings.Cut(variable, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		if err := l.apply(c, strings.Split(name[len(prefix):], l.sep), raw); err != nil {
+			return fmt.Errorf("solenodon: cannot apply environment variable %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (l *envLoader) apply(c *Container, segments []string, raw string) error {
+	keys := make([]interface{}, len(segments))
+	current := c
+	for i, segment := range segments {
+		keys[i] = envKey(current.Data(), segment)
+		current = current.Get(keys[i])
+	}
+	value, err := parseEnvValue(current.Data(), raw)
+	if err != nil {
+		return err
+	}
+	if c.Set(value, keys...) == nil {
+		return fmt.Errorf("cannot set %q", FormatPath(keys...))
+	}
+	return nil
+}
+
+// envKey returns the key in data that matches the segment.
+func envKey(data interface{}, segment string) interface{} {
+	if isMap(data) {
+		keys := childKeys(data)
+		for _, key := range keys {
+			if key == segment {
+				return key
+			}
+		}
+		for _, key := range keys {
+			if s, ok := key.(string); ok && strings.EqualFold(s, segment) {
+				return key
+			}
+		}
+		return strings.ToLower(segment)
+	}
+	if index, ok := arrayIndex(segment); ok {
+		return index
+	}
+	return strings.ToLower(segment)
+}
+
+// parseEnvValue parses raw into the type of the existing value.
+func parseEnvValue(existing interface{}, raw string) (interface{}, error) {
+	if isSlice(existing) {
+		return parseEnvList(existing, raw)
+	}
+	switch v := existing.(type) {
+	case nil, string:
+		return raw, nil
+	case bool:
+		return strconv.ParseBool(raw)
+	case int:
+		return strconv.Atoi(raw)
+	case int8, int16, int32, int64:
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return convertNumber(i, v)
+	case uint, uint8, uint16, uint32, uint64:
+		u, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return convertNumber(u, v)
+	case float32:
+		f, err := strconv.ParseFloat(raw, 32)
+		return float32(f), err
+	case float64:
+		return strconv.ParseFloat(raw, 64)
+	case time.Time:
+		return time.Parse(time.RFC3339Nano, raw)
+	case time.Duration:
+		return time.ParseDuration(raw)
+	}
+	return nil, fmt.Errorf("cannot parse %q into %T", raw, existing)
+}
+
+// parseEnvList parses a comma-separated list into a slice of the same type as the existing slice.
+// The elements are parsed into the type of the first existing element, or of the element type of a Go slice.
+func parseEnvList(existing interface{}, raw string) (interface{}, error) {
+	elements, _ := sliceElements(existing)
+	var first interface{}
+	if len(elements) > 0 {
+		first = elements[0]
+	} else if t := reflect.TypeOf(existing); (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Interface {
+		first = reflect.Zero(t.Elem()).Interface()
+	}
+	if isMap(first) || isSlice(first) {
+		return nil, fmt.Errorf("cannot parse %q into a slice of %T", raw, first)
+	}
+	list := []interface{}{}
+	if raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			element, err := parseEnvValue(first, strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, element)
+		}
+	}
+	slice, ok := sequenceLike(existing, list)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse %q into %T", raw, existing)
+	}
+	return slice, nil
+}
+
+// convertNumber converts the parsed integer to the type of the existing value, failing if it does not fit.
+func convertNumber(n interface{}, existing interface{}) (interface{}, error) {
+	var out interface{}
+	switch existing.(type) {
+	case int8:
+		out = int8(n.(int64))
+	case int16:
+		out = int16(n.(int64))
+	case int32:
+		out = int32(n.(int64))
+	case int64:
+		out = n.(int64)
+	case uint:
+		out = uint(n.(uint64))
+	case uint8:
+		out = uint8(n.(uint64))
+	case uint16:
+		out = uint16(n.(uint64))
+	case uint32:
+		out = uint32(n.(uint64))
+	case uint64:
+		out = n.(uint64)
+	}
+	if !numbersEqual(out, n) {
+		return nil, fmt.Errorf("%v is out of range for %T", n, existing)
+	}
+	return out, nil
+}
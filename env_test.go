@@ -0,0 +1,93 @@
+package solenodon
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestApplyEnv(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.Set(true, "database", "enabled")
+	container.Set(time.Second, "database", "timeout")
+	container.Set("info", "log_level")
+	environ := []string{
+		"APP_DATABASE__SERVER=10.0.0.1",
+		"APP_DATABASE__THRESHOLD=12.5",
+		"APP_OWNER__TIME=2020-01-02T03:04:05Z",
+		"APP_DATABASE__ENABLED=false",
+		"APP_DATABASE__TIMEOUT=1m",
+		"APP_DATABASE__PORTS=9000, 9001",
+		"APP_LOG_LEVEL=debug",
+		"APP_CLIENTS__0__1=epsilon",
+		"APP_HOSTS__2=beta",
+		"APP_NEW__KEY=value",
+		"OTHER_DATABASE__SERVER=ignored",
+	}
+	if err := container.ApplyEnv("APP_", WithEnviron(environ)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		keys     []interface{}
+		expected interface{}
+	}{
+		{[]interface{}{"database", "server"}, "10.0.0.1"},
+		{[]interface{}{"database", "threshold"}, 12.5},
+		{[]interface{}{"owner", "time"}, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{[]interface{}{"database", "enabled"}, false},
+		{[]interface{}{"database", "timeout"}, time.Minute},
+		{[]interface{}{"database", "ports"}, []interface{}{int64(9000), int64(9001)}},
+		{[]interface{}{"log_level"}, "debug"},
+		{[]interface{}{"clients", 0, 1}, "epsilon"},
+		{[]interface{}{"hosts"}, []interface{}{"alpha", "omega", "beta"}},
+		{[]interface{}{"new", "key"}, "value"},
+	}
+	for i, test := range tests {
+		if data := container.Get(test.keys...).Data(); !reflect.DeepEqual(data, test.expected) {
+			t.Errorf("%d, expected %v (%T), got %v (%T)", i, test.expected, test.expected, data, data)
+		}
+	}
+}
+
+func TestApplyEnvSeparator(t *testing.T) {
+	container := newJSONContainer(t, `{"Database":{"Port":8080}}`)
+	if err := container.ApplyEnv("APP_", WithEnviron([]string{"APP_database_port=9090"}), WithEnvSeparator("_")); err != nil {
+		t.Fatal(err)
+	}
+	if data := container.Get("Database", "Port").Data(); data != 9090.0 {
+		t.Errorf("expected 9090, got %v (%T)", data, data)
+	}
+}
+
+func TestApplyEnvError(t *testing.T) {
+	tests := []string{
+		"APP_PORT=abc",
+		"APP_ENABLED=maybe",
+		"APP_NESTED=value",
+		"APP_NAME__X=value",
+	}
+	for i, variable := range tests {
+		container := newJSONContainer(t, `{"port":8080,"enabled":true,"nested":{"a":1},"name":"x"}`)
+		err := container.ApplyEnv("APP_", WithEnviron([]string{variable}))
+		if err == nil {
+			t.Errorf("%d, expected error", i)
+			continue
+		}
+		if name := strings.SplitN(variable, "=", 2)[0]; !strings.Contains(err.Error(), name) {
+			t.Errorf("%d, expected error to name %s, got %q", i, name, err)
+		}
+	}
+}
+
+func TestApplyEnvHugeIndex(t *testing.T) {
+	container := newJSONContainer(t, `{"list":[1]}`)
+	if err := container.ApplyEnv("APP_", WithEnviron([]string{"APP_LIST__999999999999999=1"})); err == nil {
+		t.Error("expected error for a huge index")
+	}
+}
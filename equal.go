@@ -0,0 +1,161 @@
+package solenodon
+
+import (
+	"math"
+	"reflect"
+	"time"
+)
+
+// EqualOption configures Equal.
+type EqualOption func(*equaler)
+
+// WithFloatTolerance makes numbers equal if they differ by at most the given tolerance.
+func WithFloatTolerance(tolerance float64) EqualOption {
+	return func(e *equaler) {
+		e.tolerance = tolerance
+	}
+}
+
+// WithIgnoredPath ignores the value at the path of the given keys, relative to the compared Containers.
+// The option can be given multiple times to ignore multiple paths.
+func WithIgnoredPath(keys ...interface{}) EqualOption {
+	return func(e *equaler) {
+		e.ignored = append(e.ignored, keys)
+	}
+}
+
+// WithMissingAsNull makes a missing map key equal to a key with a nil value.
+func WithMissingAsNull() EqualOption {
+	return func(e *equaler) {
+		e.missingAsNull = true
+	}
+}
+
+// Equal reports whether the data in this Container is deeply equal to the data in the other Container.
+// Numbers are compared by value, maps and slices by their contents, and times with time.Time.Equal.
+// Two nil Containers are equal.
+func (c *Container) Equal(other *Container, opts ...EqualOption) bool {
+	e := &equaler{}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if c == nil || other == nil {
+		if e.missingAsNull {
+			return c.Data() == nil && other.Data() == nil
+		}
+		return c == nil && other == nil
+	}
+	return e.equal([]interface{}{}, c.data, other.data)
+}
+
+// valuesEqual reports whether a and b are equal in the way Equal compares them without options.
+func valuesEqual(a, b interface{}) bool {
+	return (&equaler{}).equal(nil, a, b)
+}
+
+type equaler struct {
+	tolerance     float64
+	ignored       [][]interface{}
+	missingAsNull bool
+	comparing     pointerPairs
+}
+
+func (e *equaler) equal(path []interface{}, a, b interface{}) bool {
+	if e.isIgnored(path) {
+		return true
+	}
+	kindA := valueKind(a)
+	if kindA != valueKind(b) {
+		return false
+	}
+	if kindA == kindMap || kindA == kindSlice {
+		leave, ok := e.comparing.enter(a, b)
+		if !ok {
+			// a and b contain themselves, and are equal if the rest of them is.
+			return true
+		}
+		defer leave()
+	}
+	switch kindA {
+	case kindNumber:
+		if e.tolerance > 0 {
+			x, _ := toFloat64(a)
+			y, _ := toFloat64(b)
+			return math.Abs(x-y) <= e.tolerance
+		}
+		return numbersEqual(a, b)
+	case kindTime:
+		return a.(time.Time).Equal(b.(time.Time))
+	case kindMap:
+		return e.mapsEqual(path, a, b)
+	case kindSlice:
+		elementsA, _ := sliceElements(a)
+		elementsB, _ := sliceElements(b)
+		if len(elementsA) != len(elementsB) {
+			return false
+		}
+		for i := range elementsA {
+			if !e.equal(e.childPath(path, i), elementsA[i], elementsB[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (e *equaler) mapsEqual(path []interface{}, a, b interface{}) bool {
+	keysA, keysB := childKeys(a), childKeys(b)
+	if len(keysA) != len(keysB) && !e.missingAsNull && len(e.ignored) == 0 {
+		return false
+	}
+	for _, key := range keysA {
+		valueA, _ := child(a, key)
+		valueB, reason := child(b, key)
+		keyPath := e.childPath(path, key)
+		if reason != 0 && !e.isIgnored(keyPath) && !(e.missingAsNull && valueA == nil) {
+			return false
+		}
+		if !e.equal(keyPath, valueA, valueB) {
+			return false
+		}
+	}
+	for _, key := range keysB {
+		if _, reason := child(a, key); reason == 0 {
+			continue
+		}
+		valueB, _ := child(b, key)
+		if !e.isIgnored(e.childPath(path, key)) && !(e.missingAsNull && valueB == nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// childPath returns the path of the child at the given key.
+// Paths are only tracked when there are ignored paths.
+func (e *equaler) childPath(path []interface{}, key interface{}) []interface{} {
+	if len(e.ignored) == 0 {
+		return nil
+	}
+	return append(append(make([]interface{}, 0, len(path)+1), path...), key)
+}
+
+func (e *equaler) isIgnored(path []interface{}) bool {
+	for _, ignored := range e.ignored {
+		if len(ignored) != len(path) {
+			continue
+		}
+		match := true
+		for i := range ignored {
+			if ignored[i] != path[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func TestEqualAcrossDecoders(t *testing.T) {
+	fromJSON, err := NewContainerFromBytes([]byte(`{"a":1,"b":[{"c":2.5}],"1":"one"}`), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := NewContainerFromBytes([]byte("a: 1\nb:\n- c: 2.5\n\"1\": one\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromTOML, err := NewContainerFromBytes([]byte("a = 1\n\"1\" = \"one\"\n[[b]]\nc = 2.5\n"), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromJSON.Equal(fromYAML) || !fromYAML.Equal(fromJSON) {
+		t.Error("expected JSON and YAML to be equal")
+	}
+	if !fromJSON.Equal(fromTOML) || !fromTOML.Equal(fromYAML) {
+		t.Error("expected TOML to be equal to JSON and YAML")
+	}
+	fromTOML.Get("b", 0, "c").SetData(2.6)
+	if fromJSON.Equal(fromTOML) {
+		t.Error("did not expect modified TOML to be equal to JSON")
+	}
+}
+
+func TestEqualOptions(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		opts  []EqualOption
+		equal bool
+	}{
+		{a: `{"a":1.0}`, b: `{"a":1.05}`, equal: false},
+		{a: `{"a":1.0}`, b: `{"a":1.05}`, opts: []EqualOption{WithFloatTolerance(0.1)}, equal: true},
+		{a: `{"a":1,"b":{"c":2}}`, b: `{"a":1,"b":{"c":3}}`, equal: false},
+		{a: `{"a":1,"b":{"c":2}}`, b: `{"a":1,"b":{"c":3}}`, opts: []EqualOption{WithIgnoredPath("b", "c")}, equal: true},
+		{a: `{"a":1,"b":{"c":2}}`, b: `{"a":1,"b":{}}`, opts: []EqualOption{WithIgnoredPath("b", "c")}, equal: true},
+		{a: `{"a":[1,2]}`, b: `{"a":[1,3]}`, opts: []EqualOption{WithIgnoredPath("a", 1)}, equal: true},
+		{a: `{"a":[1,2]}`, b: `{"a":[1,3]}`, opts: []EqualOption{WithIgnoredPath("a", 0)}, equal: false},
+		{a: `{"a":1,"b":null}`, b: `{"a":1}`, equal: false},
+		{a: `{"a":1,"b":null}`, b: `{"a":1}`, opts: []EqualOption{WithMissingAsNull()}, equal: true},
+		{a: `{"a":1}`, b: `{"a":1,"b":null}`, opts: []EqualOption{WithMissingAsNull()}, equal: true},
+		{a: `{"a":1}`, b: `{"a":1,"b":2}`, opts: []EqualOption{WithMissingAsNull()}, equal: false},
+	}
+	for i, test := range tests {
+		a := newJSONContainer(t, test.a)
+		b := newJSONContainer(t, test.b)
+		if equal := a.Equal(b, test.opts...); equal != test.equal {
+			t.Errorf("%d, expected equal to be %v, got %v", i, test.equal, equal)
+		}
+	}
+}
+
+func TestEqualNilContainers(t *testing.T) {
+	var container *Container
+	if !container.Equal(nil) {
+		t.Error("expected nil containers to be equal")
+	}
+	if container.Equal(NewContainer(nil)) {
+		t.Error("did not expect nil container to equal container with nil data")
+	}
+	if !container.Equal(NewContainer(nil), WithMissingAsNull()) {
+		t.Error("expected nil container to equal container with nil data when missing is null")
+	}
+}
+
+func TestEqualLargeIntegers(t *testing.T) {
+	if NewContainer(int64(1<<53 + 1)).Equal(NewContainer(int64(1 << 53))) {
+		t.Error("did not expect different large integers to be equal")
+	}
+	if !NewContainer(uint64(1 << 60)).Equal(NewContainer(int64(1 << 60))) {
+		t.Error("expected equal large integers of different types to be equal")
+	}
+}
@@ -0,0 +1,147 @@
+package solenodon
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FlattenOption configures Flatten and Unflatten.
+type FlattenOption func(*flattener)
+
+// WithKeyEscaping escapes the separator and backslashes in keys with a backslash,
+// so that keys that contain the separator survive a round trip through Flatten and Unflatten.
+func WithKeyEscaping() FlattenOption {
+	return func(f *flattener) {
+		f.escape = true
+	}
+}
+
+// WithArrays sets whether Unflatten turns numeric segments, such as the 0 in "ports.0", into slice indices.
+// The default is true. If false, numeric segments become string keys of maps.
+func WithArrays(enabled bool) FlattenOption {
+	return func(f *flattener) {
+		f.arrays = enabled
+	}
+}
+
+type flattener struct {
+	sep    string
+	escape bool
+	arrays bool
+}
+
+func newFlattener(sep string, opts []FlattenOption) *flattener {
+	f := &flattener{sep: sep, arrays: true}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Flatten returns the leaves of the Container as a flat map, with keys that join the path of each leaf with sep.
+// Empty maps and slices are kept as values, and a scalar results in a single entry with the empty key.
+func (c *Container) Flatten(sep string, opts ...FlattenOption) map[string]interface{} {
+	f := newFlattener(sep, opts)
+	flat := map[string]interface{}{}
+	c.Walk(func(n *Container) error {
+		if n.Len() > 0 {
+			return nil
+		}
+		path := n.Path()[c.Depth():]
+		segments := make([]string, len(path))
+		for i, key := range path {
+			segments[i] = f.segment(key)
+		}
+		flat[strings.Join(segments, sep)] = cloneData(n.data)
+		return nil
+	})
+	return flat
+}
+
+func (f *flattener) segment(key interface{}) string {
+	var s string
+	switch v := key.(type) {
+	case string:
+		s = v
+	case int:
+		s = strconv.Itoa(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+	if !f.escape {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	if f.sep != "" {
+		s = strings.ReplaceAll(s, f.sep, `\`+f.sep)
+	}
+	return s
+}
+
+func (f *flattener) split(key string) []string {
+	if f.sep == "" {
+		return []string{key}
+	}
+	if !f.escape {
+		return strings.Split(key, f.sep)
+	}
+	var segments []string
+	var segment strings.Builder
+	for i := 0; i < len(key); {
+		switch {
+		case key[i] == '\\' && i+1 < len(key):
+			i++
+			if strings.HasPrefix(key[i:], f.sep) {
+				segment.WriteString(f.sep)
+				i += len(f.sep)
+			} else {
+				segment.WriteByte(key[i])
+				i++
+			}
+		case strings.HasPrefix(key[i:], f.sep):
+			segments = append(segments, segment.String())
+			segment.Reset()
+			i += len(f.sep)
+		default:
+			segment.WriteByte(key[i])
+			i++
+		}
+	}
+	return append(segments, segment.String())
+}
+
+// Unflatten builds a Container from a flat map, as returned by Flatten, by splitting each key on sep.
+// Numeric segments become slice indices unless WithArrays(false) is given, and the values are copied.
+// An error is returned if two keys conflict, e.g. "a" and "a.b", or if an index exceeds MaxSliceGrowth.
+func Unflatten(flat map[string]interface{}, sep string, opts ...FlattenOption) (*Container, error) {
+	f := newFlattener(sep, opts)
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	container := NewContainer(nil)
+	for _, key := range keys {
+		var path []interface{}
+		if key != "" {
+			for _, segment := range f.split(key) {
+				if index, ok := arrayIndex(segment); ok && f.arrays {
+					path = append(path, index)
+				} else {
+					path = append(path, segment)
+				}
+			}
+		}
+		result, index, reason := container.set(cloneData(flat[key]), path)
+		switch {
+		case reason == ReasonIndexOutOfRange:
+			return nil, fmt.Errorf("solenodon: cannot unflatten key %q: index %v grows the slice by more than %d",
+				key, path[index], MaxSliceGrowth)
+		case result == nil:
+			return nil, fmt.Errorf("solenodon: cannot unflatten key %q: it conflicts with another key", key)
+		}
+	}
+	return container, nil
+}
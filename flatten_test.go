@@ -0,0 +1,97 @@
+package solenodon
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestFlatten(t *testing.T) {
+	container := newJSONContainer(t, `{"database":{"ports":[8080,8081],"server":"127.0.0.1","tags":[],"extra":{}},"a.b":true}`)
+	expected := map[string]interface{}{
+		"database.ports.0": 8080.0,
+		"database.ports.1": 8081.0,
+		"database.server":  "127.0.0.1",
+		"database.tags":    []interface{}{},
+		"database.extra":   map[string]interface{}{},
+		"a.b":              true,
+	}
+	if flat := container.Flatten("."); !reflect.DeepEqual(flat, expected) {
+		t.Errorf("expected %v, got %v", expected, flat)
+	}
+	if flat := container.Get("database", "ports").Flatten("_"); !reflect.DeepEqual(flat, map[string]interface{}{"0": 8080.0, "1": 8081.0}) {
+		t.Errorf("unexpected flattened child %v", flat)
+	}
+	if flat := NewContainer(1).Flatten("."); !reflect.DeepEqual(flat, map[string]interface{}{"": 1}) {
+		t.Errorf("unexpected flattened leaf %v", flat)
+	}
+}
+
+func TestFlattenUnflattenRoundTrip(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.Set("x", "servers", "gamma.delta", `back\slash`)
+	flat := container.Flatten("__", WithKeyEscaping())
+	if flat["database__ports__2"] != int64(8081) {
+		t.Errorf("unexpected flattened port %v", flat["database__ports__2"])
+	}
+	out, err := Unflatten(flat, "__", WithKeyEscaping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Equal(container) {
+		t.Errorf("expected %v, got %v", container.Data(), out.Data())
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	flat := map[string]interface{}{
+		"a.b":   1,
+		"a.c.1": 2,
+		"d.01":  3,
+	}
+	out, err := Unflatten(flat, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"a": map[string]interface{}{"b": 1, "c": []interface{}{nil, 2}},
+		"d": map[string]interface{}{"01": 3},
+	}
+	if !reflect.DeepEqual(out.Data(), expected) {
+		t.Errorf("expected %v, got %v", expected, out.Data())
+	}
+	out, err = Unflatten(flat, ".", WithArrays(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out.Get("a", "c").Data(), map[string]interface{}{"1": 2}) {
+		t.Errorf("expected map without arrays, got %v", out.Get("a", "c").Data())
+	}
+}
+
+func TestUnflattenConflict(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"a": 1, "a.b": 2},
+		{"a.0": 1, "a.b": 2},
+		{"": 1, "a": 2},
+	}
+	for i, flat := range tests {
+		if _, err := Unflatten(flat, "."); err == nil || !strings.Contains(err.Error(), "conflicts") {
+			t.Errorf("%d, expected conflict error, got %v", i, err)
+		}
+	}
+}
+
+func TestUnflattenHugeIndex(t *testing.T) {
+	for i, key := range []string{"a.99999999999999999", "a.999999999999999", "a.1025", "a.5000"} {
+		_, err := Unflatten(map[string]interface{}{key: 1}, ".")
+		if err == nil || strings.Contains(err.Error(), "conflicts") {
+			t.Errorf("%d, expected error for a huge index, got %v", i, err)
+		}
+	}
+}
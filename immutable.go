@@ -0,0 +1,151 @@
+package solenodon
+
+import "reflect"
+
+// Immutable is a persistent, read-only tree of data, which With and Without share with the Immutable they return.
+// Only the nodes along the path of a change are copied, which fails for pointers and data with a custom NodeAdapter.
+type Immutable struct {
+	data interface{}
+}
+
+// NewImmutable returns a new Immutable holding a deep copy of the given data, see Clone.
+// Values that Clone shares must not be modified afterwards.
+func NewImmutable(data interface{}) *Immutable {
+	return &Immutable{data: cloneData(data)}
+}
+
+// Data returns the data in the Immutable. It is shared with other snapshots, so it must not be modified.
+func (im *Immutable) Data() interface{} {
+	if im == nil {
+		return nil
+	}
+	return im.data
+}
+
+// Get returns an Immutable containing the value following the path of the given keys, or nil if no result was found.
+// The result shares its data with this Immutable.
+func (im *Immutable) Get(keys ...interface{}) *Immutable {
+	if im == nil {
+		return nil
+	}
+	c := NewContainer(im.data).Get(keys...)
+	if c == nil {
+		return nil
+	}
+	return &Immutable{data: c.data}
+}
+
+// Has returns true if the Immutable has a value for the given keys.
+func (im *Immutable) Has(keys ...interface{}) bool {
+	return im.Get(keys...) != nil
+}
+
+// Keys returns the keys of the children of the Immutable, see Container.Keys.
+func (im *Immutable) Keys() []interface{} {
+	return childKeys(im.Data())
+}
+
+// With returns a new Immutable in which the given value is set at the end of the path of the given keys,
+// as by Container.Set. The result is nil if the value could not be set.
+func (im *Immutable) With(value interface{}, keys ...interface{}) *Immutable {
+	if im == nil {
+		return nil
+	}
+	root, ok := im.copyPath(keys)
+	if !ok || root.Set(cloneData(value), keys...) == nil {
+		return nil
+	}
+	return &Immutable{data: root.data}
+}
+
+// Without returns a new Immutable in which the value at the end of the path of the given keys is deleted,
+// as by Container.Delete. The result is nil if the value could not be deleted.
+func (im *Immutable) Without(keys ...interface{}) *Immutable {
+	if im == nil {
+		return nil
+	}
+	if !im.Has(keys...) {
+		return im
+	}
+	if len(keys) == 0 {
+		return &Immutable{}
+	}
+	root, ok := im.copyPath(keys[:len(keys)-1])
+	if !ok {
+		return nil
+	}
+	return &Immutable{data: root.Delete(keys...).data}
+}
+
+// copyPath returns a root Container in which the nodes along the path of the given keys are shallow copies,
+// so that they can be modified without affecting this Immutable. Copying stops at the first missing key.
+func (im *Immutable) copyPath(keys []interface{}) (*Container, bool) {
+	data, ok := shallowCopy(im.data)
+	if !ok {
+		return nil, false
+	}
+	root := NewContainer(data)
+	current := root
+	for _, key := range keys {
+		next := current.Get(key)
+		if next == nil {
+			break
+		}
+		data, ok := shallowCopy(next.data)
+		if !ok || next.SetData(data) == nil {
+			return nil, false
+		}
+		current = next
+	}
+	return root, true
+}
+
+// shallowCopy returns a copy of the given map or slice that shares its children.
+// Values without children are returned as is, as are arrays and structs, which are copied when they are modified.
+// The result is false if the data cannot be copied.
+func shallowCopy(data interface{}) (interface{}, bool) {
+	switch w := data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(w))
+		for k, v := range w {
+			m[k] = v
+		}
+		return m, true
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(w))
+		for k, v := range w {
+			m[k] = v
+		}
+		return m, true
+	case []interface{}:
+		return append([]interface{}{}, w...), true
+	case []map[string]interface{}:
+		return append([]map[string]interface{}{}, w...), true
+	}
+	if _, ok := adapterOf(data).(reflectAdapter); !ok {
+		return nil, false
+	}
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return data, true
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return m.Interface(), true
+	case reflect.Slice:
+		if v.IsNil() {
+			return data, true
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		return s.Interface(), true
+	case reflect.Ptr:
+		return data, v.IsNil()
+	}
+	return data, true
+}
@@ -0,0 +1,114 @@
+package solenodon
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestImmutableWith(t *testing.T) {
+	raw := newJSONContainer(t, `{"database":{"server":"127.0.0.1","ports":[8080]},"owner":{"name":"macabot"}}`).Data()
+	v1 := NewImmutable(raw)
+	raw.(map[string]interface{})["owner"] = "changed"
+	if data := v1.Get("owner", "name").Data(); data != "macabot" {
+		t.Errorf("expected NewImmutable to copy its input, got %v", data)
+	}
+
+	v2 := v1.With("10.0.0.1", "database", "server")
+	if data := v1.Get("database", "server").Data(); data != "127.0.0.1" {
+		t.Errorf("expected old snapshot to be unchanged, got %v", data)
+	}
+	if data := v2.Get("database", "server").Data(); data != "10.0.0.1" {
+		t.Errorf("expected new value, got %v", data)
+	}
+	if !sameNode(v1.Get("owner").Data(), v2.Get("owner").Data()) {
+		t.Error("expected unchanged subtree to be shared")
+	}
+	if !sameNode(v1.Get("database", "ports").Data(), v2.Get("database", "ports").Data()) {
+		t.Error("expected unchanged sibling to be shared")
+	}
+	if sameNode(v1.Get("database").Data(), v2.Get("database").Data()) {
+		t.Error("expected node on the path to be copied")
+	}
+
+	v3 := v2.With(8081, "database", "ports", Index(-1)).With([]interface{}{"x"}, "new", 1)
+	if data := v3.Get("database", "ports").Data(); !reflect.DeepEqual(data, []interface{}{8081}) {
+		t.Errorf("unexpected ports %v", data)
+	}
+	if data := v2.Get("database", "ports").Data(); !reflect.DeepEqual(data, []interface{}{8080.0}) {
+		t.Errorf("expected old ports to be unchanged, got %v", data)
+	}
+	if data := v3.Get("new").Data(); !reflect.DeepEqual(data, []interface{}{nil, []interface{}{"x"}}) {
+		t.Errorf("unexpected new value %v", data)
+	}
+	if v3.With(1, "owner", "name", "first") != nil {
+		t.Error("expected nil when a string is in the way")
+	}
+}
+
+func TestImmutableWithout(t *testing.T) {
+	v1 := NewImmutable(map[string]interface{}{
+		"items": []interface{}{1, 2, 3},
+		"meta":  map[string]interface{}{"a": 1, "b": 2},
+	})
+	v2 := v1.Without("items", 0).Without("meta", "a")
+	if data := v2.Data(); !reflect.DeepEqual(data, map[string]interface{}{
+		"items": []interface{}{2, 3},
+		"meta":  map[string]interface{}{"b": 2},
+	}) {
+		t.Errorf("unexpected data %v", data)
+	}
+	if data := v1.Data(); !reflect.DeepEqual(data, map[string]interface{}{
+		"items": []interface{}{1, 2, 3},
+		"meta":  map[string]interface{}{"a": 1, "b": 2},
+	}) {
+		t.Errorf("expected old snapshot to be unchanged, got %v", data)
+	}
+	if v2.Without("missing") != v2 {
+		t.Error("expected Without of a missing key to return the same Immutable")
+	}
+	if data := v2.Without().Data(); data != nil {
+		t.Errorf("expected nil data, got %v", data)
+	}
+}
+
+func TestImmutableGoValues(t *testing.T) {
+	type server struct {
+		Host string            `json:"host"`
+		Tags map[string]string `json:"tags"`
+	}
+	v1 := NewImmutable(map[string]interface{}{"server": server{Host: "alpha", Tags: map[string]string{"zone": "eu"}}})
+	v2 := v1.With("us", "server", "tags", "zone")
+	if data := v1.Get("server", "tags", "zone").Data(); data != "eu" {
+		t.Errorf("expected old snapshot to be unchanged, got %v", data)
+	}
+	if data := v2.Get("server", "tags", "zone").Data(); data != "us" {
+		t.Errorf("expected new value, got %v", data)
+	}
+	if NewImmutable(map[string]interface{}{"p": &server{}}).With("beta", "p", "host") != nil {
+		t.Error("expected nil when a pointer is on the path")
+	}
+}
+
+func TestImmutableConcurrentReaders(t *testing.T) {
+	current := NewImmutable(map[string]interface{}{"version": 0})
+	snapshot := current
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if data := snapshot.Get("version").Data(); data != 0 {
+				t.Errorf("expected snapshot to keep version 0, got %v", data)
+				return
+			}
+		}
+	}()
+	for i := 1; i <= 100; i++ {
+		current = current.With(i, "version")
+	}
+	wg.Wait()
+	if data := current.Get("version").Data(); data != 100 {
+		t.Errorf("expected version 100, got %v", data)
+	}
+}
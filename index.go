@@ -0,0 +1,22 @@
+package solenodon
+
+// Index is a key for an element of a slice or array that counts from the end if it is negative, e.g. Index(-1).
+// Unlike an int key, an Index never matches a map key. It is resolved to an int in the Path of the result.
+type Index int
+
+// resolveIndex returns the int index to which an Index key refers in the given data. Other keys are returned as is.
+// The result is false for an Index and data that is not a sequence.
+func resolveIndex(data, key interface{}) (interface{}, bool) {
+	index, ok := key.(Index)
+	if !ok {
+		return key, true
+	}
+	length, ok := arrayLength(data)
+	if !ok {
+		return nil, false
+	}
+	if index < 0 {
+		return int(index) + length, true
+	}
+	return int(index), true
+}
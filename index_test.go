@@ -0,0 +1,123 @@
+package solenodon
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestIndexGet(t *testing.T) {
+	container := newJSONContainer(t, `{"releases":[{"version":"1.0"},{"version":"1.1"},{"version":"2.0"}]}`)
+	tests := []struct {
+		keys     []interface{}
+		expected interface{}
+	}{
+		{[]interface{}{"releases", Index(-1), "version"}, "2.0"},
+		{[]interface{}{"releases", Index(-3), "version"}, "1.0"},
+		{[]interface{}{"releases", Index(1), "version"}, "1.1"},
+		{[]interface{}{"releases", Index(-4)}, nil},
+		{[]interface{}{"releases", Index(3)}, nil},
+		{[]interface{}{"releases", -1}, nil},
+	}
+	for i, test := range tests {
+		if data := container.Get(test.keys...).Data(); !reflect.DeepEqual(data, test.expected) {
+			t.Errorf("%d, expected %v, got %v", i, test.expected, data)
+		}
+	}
+	if last := container.Get("releases", Index(-1)); last.Key() != 2 || last.PathString() != "releases[2]" {
+		t.Errorf("expected resolved key 2, got %v", last.Key())
+	}
+	if data := NewContainer([]string{"a", "b"}).Get(Index(-1)).Data(); data != "b" {
+		t.Errorf("expected b from Go slice, got %v", data)
+	}
+}
+
+func TestIndexYAMLMap(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte("-1: minus one\n1: one\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := container.Get(-1).Data(); data != "minus one" {
+		t.Errorf("expected int key to match, got %v", data)
+	}
+	if container.Has(Index(-1)) {
+		t.Error("expected Index not to match a map key")
+	}
+}
+
+func TestIndexModify(t *testing.T) {
+	container := newJSONContainer(t, `{"items":[1,2,3]}`)
+	container.Get("items", Index(-1)).SetData(30)
+	container.Delete("items", Index(-3))
+	if data := container.Get("items").Data(); !reflect.DeepEqual(data, []interface{}{2.0, 30}) {
+		t.Errorf("unexpected items %v", data)
+	}
+	container.Set("last", "items", Index(-1))
+	container.Set("first", "new", Index(0))
+	if data := container.Get("items").Data(); !reflect.DeepEqual(data, []interface{}{2.0, "last"}) {
+		t.Errorf("unexpected items %v", data)
+	}
+	if data := container.Get("new").Data(); !reflect.DeepEqual(data, []interface{}{"first"}) {
+		t.Errorf("unexpected new slice %v", data)
+	}
+	if container.Set("x", "empty", Index(-1)) != nil {
+		t.Error("expected nil when setting Index(-1) of a new slice")
+	}
+}
+
+func TestInsertIndex(t *testing.T) {
+	tests := []struct {
+		index    Index
+		expected []interface{}
+	}{
+		{Index(-1), []interface{}{1, "x", 2}},
+		{Index(-2), []interface{}{"x", 1, 2}},
+		{Index(2), []interface{}{1, 2, "x"}},
+		{Index(-3), nil},
+		{Index(3), nil},
+	}
+	for i, test := range tests {
+		container := NewContainer([]interface{}{1, 2})
+		result := container.Insert(test.index, "x")
+		if test.expected == nil {
+			if result != nil {
+				t.Errorf("%d, expected nil, got %v", i, result.Data())
+			}
+			continue
+		}
+		if result == nil {
+			t.Errorf("%d, unexpected nil", i)
+			continue
+		}
+		if !reflect.DeepEqual(container.Data(), test.expected) {
+			t.Errorf("%d, expected %v, got %v", i, test.expected, container.Data())
+		}
+	}
+	if NewContainer([]interface{}{1}).Insert("0", "x") != nil {
+		t.Error("expected nil for a string index")
+	}
+}
+
+func TestIndexNotSequence(t *testing.T) {
+	container := NewContainer(map[interface{}]interface{}{})
+	if container.Set(5, Index(-1)) != nil {
+		t.Error("expected nil when setting an Index in a map")
+	}
+	if len(container.Data().(map[interface{}]interface{})) != 0 {
+		t.Errorf("expected no key to be added, got %v", container.Data())
+	}
+	_, err := container.Lookup("a", Index(-5))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	container.Set(map[string]interface{}{}, "a")
+	_, err = container.Lookup("a", Index(-5))
+	pathErr, ok := err.(*PathError)
+	if !ok || pathErr.Reason != ReasonInvalidKey {
+		t.Fatalf("expected invalid key, got %v", err)
+	}
+	if expected := `solenodon: cannot get "a[-5]": invalid key type at "a[-5]" (key -5 in map[string]interface {})`; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
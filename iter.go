@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package solenodon
+
+import "iter"
+
+// All returns an iterator over the children of the Container, as returned by Children.
+func (c *Container) All() iter.Seq[*Container] {
+	return func(yield func(*Container) bool) {
+		for _, key := range c.Keys() {
+			child := c.Get(key)
+			if child == nil {
+				continue
+			}
+			if !yield(child) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns an iterator over the keys and children of the Container, in the order of Keys.
+func (c *Container) Entries() iter.Seq2[interface{}, *Container] {
+	return func(yield func(interface{}, *Container) bool) {
+		for _, key := range c.Keys() {
+			child := c.Get(key)
+			if child == nil {
+				continue
+			}
+			if !yield(key, child) {
+				return
+			}
+		}
+	}
+}
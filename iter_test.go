@@ -0,0 +1,34 @@
+//go:build go1.23
+
+package solenodon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	container := newJSONContainer(t, `{"b":2,"a":1,"c":3}`)
+	var data []interface{}
+	for child := range container.All() {
+		data = append(data, child.Data())
+		if child.Key() == "b" {
+			break
+		}
+	}
+	expected := []interface{}{1.0, 2.0}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+}
+
+func TestEntriesSetData(t *testing.T) {
+	container := newJSONContainer(t, `{"items":[1,2,3]}`)
+	for key, child := range container.Get("items").Entries() {
+		child.SetData(key.(int) * 10)
+	}
+	expected := []interface{}{0, 10, 20}
+	if !reflect.DeepEqual(container.Get("items").Data(), expected) {
+		t.Errorf("expected %v, got %v", expected, container.Get("items").Data())
+	}
+}
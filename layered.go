@@ -0,0 +1,265 @@
+package solenodon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Locator returns the line at which the value at the path of the given keys is defined, or 0 if it is unknown.
+type Locator func(keys ...interface{}) int
+
+// Layer is a named Container in a Layered view.
+type Layer struct {
+	// Name identifies the layer, e.g. "defaults", "file", "env" or "flags".
+	Name string
+	// File is the file from which the data was read, if any.
+	File string
+	// Container contains the data of the layer.
+	Container *Container
+	// Locator, if not nil, finds the lines of values in File. See JSONLocator and YAMLLocator.
+	Locator Locator
+}
+
+// Source describes where a value in a Layered view was found.
+type Source struct {
+	// Layer is the name of the layer that supplied the value.
+	Layer string
+	// File is the file of the layer.
+	File string
+	// Line is the line at which the value is defined in File, or 0 if it is unknown.
+	Line int
+}
+
+// Layered is a read-only view of a stack of layers, in which a value in a layer overrides the values at the same path
+// in the layers below it. A value that is not a map also hides the children below it.
+type Layered struct {
+	layers []Layer
+}
+
+// NewLayered returns a Layered view of the given layers, from the lowest to the highest precedence.
+func NewLayered(layers ...Layer) *Layered {
+	return &Layered{layers: append([]Layer{}, layers...)}
+}
+
+// Layers returns the layers of the view, from the lowest to the highest precedence.
+func (l *Layered) Layers() []Layer {
+	return append([]Layer{}, l.layers...)
+}
+
+// Get returns a Container containing the value at the path of the given keys in the highest layer that has one,
+// A map is returned as it is found in that layer; use Keys to list the keys of all layers.
+// The returned Container belongs to the layer, so its data must not be modified.
+func (l *Layered) Get(keys ...interface{}) *Container {
+	_, result := l.find(keys)
+	return result
+}
+
+// Has returns true if any layer has a value for the given keys.
+func (l *Layered) Has(keys ...interface{}) bool {
+	return l.Get(keys...) != nil
+}
+
+// Keys returns the keys of the children at the path of the given keys in all layers, ordered as by Container.Keys.
+func (l *Layered) Keys(keys ...interface{}) []interface{} {
+	if l == nil {
+		return nil
+	}
+	var union []interface{}
+	seen := map[interface{}]bool{}
+	for _, layer := range l.layers[l.lowest(keys):] {
+		for _, key := range layer.Container.Get(keys...).Keys() {
+			if !seen[key] {
+				seen[key] = true
+				union = append(union, key)
+			}
+		}
+	}
+	sort.SliceStable(union, func(i, j int) bool { return lessKey(union[i], union[j]) })
+	return union
+}
+
+// Source returns where the value returned by Get for the given keys comes from.
+// The result is false if no layer has a value for the given keys.
+func (l *Layered) Source(keys ...interface{}) (Source, bool) {
+	index, result := l.find(keys)
+	if result == nil {
+		return Source{}, false
+	}
+	layer := l.layers[index]
+	source := Source{Layer: layer.Name, File: layer.File}
+	if layer.Locator != nil {
+		source.Line = layer.Locator(result.Path()...)
+	}
+	return source, true
+}
+
+// find returns the index of the highest layer that has a value for the given keys, and that value.
+func (l *Layered) find(keys []interface{}) (int, *Container) {
+	if l == nil {
+		return -1, nil
+	}
+	for i := len(l.layers) - 1; i >= l.lowest(keys); i-- {
+		if result := l.layers[i].Container.Get(keys...); result != nil {
+			return i, result
+		}
+	}
+	return -1, nil
+}
+
+// lowest returns the index of the lowest layer that is not hidden at the path of the given keys,
+// i.e. the highest layer in which a key along the path holds a value that is not a map.
+func (l *Layered) lowest(keys []interface{}) int {
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		for depth := 1; depth <= len(keys); depth++ {
+			if result := l.layers[i].Container.Get(keys[:depth]...); result != nil && !isMap(result.data) {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// JSONLocator returns a Locator for the given JSON document.
+// The line of a map value is the line of its key.
+func JSONLocator(b []byte) (Locator, error) {
+	p := &jsonLocator{b: b, dec: json.NewDecoder(bytes.NewReader(b)), lines: map[string]int{}}
+	if err := p.value([]interface{}{}, p.line()); err != nil {
+		return nil, err
+	}
+	return func(keys ...interface{}) int {
+		return p.lines[FormatPointer(keys...)]
+	}, nil
+}
+
+type jsonLocator struct {
+	b     []byte
+	dec   *json.Decoder
+	lines map[string]int
+}
+
+// line returns the line of the next token.
+func (p *jsonLocator) line() int {
+	offset := int(p.dec.InputOffset())
+	for offset < len(p.b) && bytes.IndexByte([]byte(" \t\r\n,:"), p.b[offset]) != -1 {
+		offset++
+	}
+	return bytes.Count(p.b[:offset], []byte("\n")) + 1
+}
+
+func (p *jsonLocator) value(path []interface{}, line int) error {
+	p.lines[FormatPointer(path...)] = line
+	childPath := func(key interface{}) []interface{} {
+		return append(append(make([]interface{}, 0, len(path)+1), path...), key)
+	}
+	token, err := p.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		for p.dec.More() {
+			line := p.line()
+			key, err := p.dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := p.value(childPath(key), line); err != nil {
+				return err
+			}
+		}
+		_, err = p.dec.Token()
+	case json.Delim('['):
+		for i := 0; p.dec.More(); i++ {
+			if err := p.value(childPath(i), p.line()); err != nil {
+				return err
+			}
+		}
+		_, err = p.dec.Token()
+	}
+	return err
+}
+
+// YAMLLocator returns a Locator for the given YAML document, as decoded by gopkg.in/yaml.v3.
+// The line of a map value is the line of its key. Values merged with "<<" have the line at which they are defined.
+func YAMLLocator(b []byte) (Locator, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	p := &yamlLocator{lines: map[string]int{}, aliases: map[*yaml.Node]bool{}}
+	if err := p.value([]interface{}{}, &doc, doc.Line); err != nil {
+		return nil, err
+	}
+	return func(keys ...interface{}) int {
+		return p.lines[FormatPointer(keys...)]
+	}, nil
+}
+
+type yamlLocator struct {
+	lines map[string]int
+	// aliases holds the aliases that are being followed, to reject an alias that refers to itself.
+	aliases map[*yaml.Node]bool
+}
+
+// value records the line of the node and of its descendants. Lines that are already recorded are kept,
+// so that the keys of a map take precedence over the keys that are merged into it.
+func (p *yamlLocator) value(path []interface{}, node *yaml.Node, line int) error {
+	pointer := FormatPointer(path...)
+	if _, ok := p.lines[pointer]; !ok {
+		p.lines[pointer] = line
+	}
+	childPath := func(key interface{}) []interface{} {
+		return append(append(make([]interface{}, 0, len(path)+1), path...), key)
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return p.value(path, node.Content[0], node.Content[0].Line)
+		}
+	case yaml.AliasNode:
+		if p.aliases[node] {
+			return fmt.Errorf("solenodon: alias %q refers to itself", node.Value)
+		}
+		p.aliases[node] = true
+		defer delete(p.aliases, node)
+		return p.value(path, node.Alias, line)
+	case yaml.SequenceNode:
+		for i, element := range node.Content {
+			if err := p.value(childPath(i), element, element.Line); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		var merges []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, value := node.Content[i], node.Content[i+1]
+			if keyNode.Tag == "!!merge" {
+				merges = append(merges, value)
+				continue
+			}
+			var key interface{}
+			if err := keyNode.Decode(&key); err != nil {
+				return err
+			}
+			if err := p.value(childPath(key), value, keyNode.Line); err != nil {
+				return err
+			}
+		}
+		for _, merge := range merges {
+			maps := []*yaml.Node{merge}
+			if merge.Kind == yaml.SequenceNode {
+				maps = merge.Content
+			}
+			for _, m := range maps {
+				if err := p.value(path, m, line); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
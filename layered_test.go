@@ -0,0 +1,187 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLayered(t *testing.T) {
+	defaults := NewContainer(map[string]interface{}{
+		"database": map[string]interface{}{"server": "localhost", "port": 5432, "user": "admin"},
+		"debug":    false,
+	})
+	raw := []byte(`{
+	"database": {
+		"server": "10.0.0.1",
+		"port": 6432
+	},
+	"hosts": [
+		"alpha",
+		"omega"
+	]
+}`)
+	file, err := NewContainerFromBytes(raw, json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	locator, err := JSONLocator(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := NewContainer(map[string]interface{}{})
+	if err := env.ApplyEnv("APP_", WithEnviron([]string{"APP_DATABASE__SERVER=10.0.0.2"})); err != nil {
+		t.Fatal(err)
+	}
+	layered := NewLayered(
+		Layer{Name: "defaults", Container: defaults},
+		Layer{Name: "file", File: "config.json", Container: file, Locator: locator},
+		Layer{Name: "env", Container: env},
+	)
+
+	tests := []struct {
+		keys     []interface{}
+		expected interface{}
+		source   Source
+	}{
+		{[]interface{}{"database", "server"}, "10.0.0.2", Source{Layer: "env"}},
+		{[]interface{}{"database", "port"}, 6432.0, Source{Layer: "file", File: "config.json", Line: 4}},
+		{[]interface{}{"database", "user"}, "admin", Source{Layer: "defaults"}},
+		{[]interface{}{"hosts", 1}, "omega", Source{Layer: "file", File: "config.json", Line: 8}},
+		{[]interface{}{"debug"}, false, Source{Layer: "defaults"}},
+	}
+	for i, test := range tests {
+		if data := layered.Get(test.keys...).Data(); !reflect.DeepEqual(data, test.expected) {
+			t.Errorf("%d, expected %v, got %v", i, test.expected, data)
+		}
+		source, ok := layered.Source(test.keys...)
+		if !ok || source != test.source {
+			t.Errorf("%d, expected source %+v, got %+v (%t)", i, test.source, source, ok)
+		}
+	}
+
+	if layered.Has("missing") {
+		t.Error("expected missing key to be absent")
+	}
+	if _, ok := layered.Source("missing"); ok {
+		t.Error("expected no source for missing key")
+	}
+	if keys := layered.Keys("database"); !reflect.DeepEqual(keys, []interface{}{"port", "server", "user"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	if keys := layered.Keys(); !reflect.DeepEqual(keys, []interface{}{"database", "debug", "hosts"}) {
+		t.Errorf("unexpected root keys %v", keys)
+	}
+}
+
+func TestLayeredHidesReplacedSubtree(t *testing.T) {
+	layered := NewLayered(
+		Layer{Name: "defaults", Container: NewContainer(map[string]interface{}{
+			"database": map[string]interface{}{"server": "localhost"},
+			"cache":    map[string]interface{}{"size": 10},
+		})},
+		Layer{Name: "env", Container: NewContainer(map[string]interface{}{"database": "postgres://x", "cache": nil})},
+		Layer{Name: "flags", Container: NewContainer(map[string]interface{}{"cache": map[string]interface{}{"ttl": 5}})},
+	)
+	tests := []struct {
+		keys     []interface{}
+		expected interface{}
+		keyCount int
+	}{
+		{[]interface{}{"database"}, "postgres://x", 0},
+		{[]interface{}{"database", "server"}, nil, 0},
+		{[]interface{}{"cache", "size"}, nil, 0},
+		{[]interface{}{"cache", "ttl"}, 5, 0},
+		{[]interface{}{"cache"}, map[string]interface{}{"ttl": 5}, 1},
+	}
+	for i, test := range tests {
+		if data := layered.Get(test.keys...).Data(); !reflect.DeepEqual(data, test.expected) {
+			t.Errorf("%d, expected %v, got %v", i, test.expected, data)
+		}
+		if keys := layered.Keys(test.keys...); len(keys) != test.keyCount {
+			t.Errorf("%d, expected %d keys, got %v", i, test.keyCount, keys)
+		}
+	}
+	if _, ok := layered.Source("database", "server"); ok {
+		t.Error("expected no source for hidden key")
+	}
+}
+
+func TestJSONLocator(t *testing.T) {
+	raw := []byte("{\"a\": 1,\n\"b\": [\n{\"c\": true}, 2\n]}")
+	locator, err := JSONLocator(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		keys     []interface{}
+		expected int
+	}{
+		{[]interface{}{}, 1},
+		{[]interface{}{"a"}, 1},
+		{[]interface{}{"b"}, 2},
+		{[]interface{}{"b", 0}, 3},
+		{[]interface{}{"b", 0, "c"}, 3},
+		{[]interface{}{"b", 1}, 3},
+		{[]interface{}{"missing"}, 0},
+	}
+	for i, test := range tests {
+		if line := locator(test.keys...); line != test.expected {
+			t.Errorf("%d, expected line %d, got %d", i, test.expected, line)
+		}
+	}
+	if _, err := JSONLocator([]byte(`{"a":`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestYAMLLocator(t *testing.T) {
+	raw := []byte(`base: &base
+  host: localhost
+  port: 5432
+database:
+  <<: *base
+  port: 6432
+hosts:
+  - alpha
+  - omega
+1: one
+`)
+	locator, err := YAMLLocator(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		keys     []interface{}
+		expected int
+	}{
+		{[]interface{}{}, 1},
+		{[]interface{}{"base", "port"}, 3},
+		{[]interface{}{"database"}, 4},
+		{[]interface{}{"database", "host"}, 2},
+		{[]interface{}{"database", "port"}, 6},
+		{[]interface{}{"hosts", 1}, 9},
+		{[]interface{}{1}, 10},
+		{[]interface{}{"missing"}, 0},
+	}
+	for i, test := range tests {
+		if line := locator(test.keys...); line != test.expected {
+			t.Errorf("%d, expected line %d, got %d", i, test.expected, line)
+		}
+	}
+	if _, err := YAMLLocator([]byte("a: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+
+	file, err := NewContainerFromBytes(raw, yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layered := NewLayered(Layer{Name: "file", File: "config.yaml", Container: file, Locator: locator})
+	source, ok := layered.Source("database", "port")
+	if expected := (Source{Layer: "file", File: "config.yaml", Line: 6}); !ok || source != expected {
+		t.Errorf("expected source %+v, got %+v (%t)", expected, source, ok)
+	}
+}
@@ -0,0 +1,72 @@
+package solenodon
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Reason describes why a lookup failed.
+type Reason int
+
+const (
+	// ReasonKeyNotFound means that a map does not contain the key.
+	ReasonKeyNotFound Reason = iota + 1
+	// ReasonIndexOutOfRange means that the index is negative or not less than the length of the slice.
+	ReasonIndexOutOfRange
+	// ReasonInvalidKey means that the type of the key does not fit the node, e.g. a string key for a slice.
+	ReasonInvalidKey
+	// ReasonNotTraversable means that the node has no children, e.g. a string or a number.
+	ReasonNotTraversable
+	// ReasonNilContainer means that the lookup was done on a nil Container.
+	ReasonNilContainer
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonKeyNotFound:
+		return "key not found"
+	case ReasonIndexOutOfRange:
+		return "index out of range"
+	case ReasonInvalidKey:
+		return "invalid key type"
+	case ReasonNotTraversable:
+		return "node is not traversable"
+	case ReasonNilContainer:
+		return "nil container"
+	}
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// PathError is returned by Lookup when the path of the given keys could not be followed.
+type PathError struct {
+	// Path contains all requested keys.
+	Path []interface{}
+	// Index is the index in Path of the key that could not be followed.
+	Index int
+	// Type is the type of the node in which the key at Index was looked up, or nil if that node is nil.
+	Type reflect.Type
+	// Reason describes why the key could not be followed.
+	Reason Reason
+}
+
+func (e *PathError) Error() string {
+	if e.Reason == ReasonNilContainer {
+		return fmt.Sprintf("solenodon: cannot get %q: %s", FormatPath(e.Path...), e.Reason)
+	}
+	return fmt.Sprintf("solenodon: cannot get %q: %s at %q (key %v in %v)",
+		FormatPath(e.Path...), e.Reason, FormatPath(e.Path[:e.Index+1]...), e.Path[e.Index], e.Type)
+}
+
+// Lookup returns a Container containing the value following the path of the given keys.
+// If no result was found, a *PathError is returned that describes where and why the path could not be followed.
+func (c *Container) Lookup(keys ...interface{}) (*Container, error) {
+	if c == nil {
+		return nil, &PathError{Path: keys, Reason: ReasonNilContainer}
+	}
+	result, index, reason := c.lookup(keys)
+	if result == nil {
+		node := c.Get(keys[:index]...)
+		return nil, &PathError{Path: keys, Index: index, Type: reflect.TypeOf(node.data), Reason: reason}
+	}
+	return result, nil
+}
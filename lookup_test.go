@@ -0,0 +1,75 @@
+package solenodon
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestLookupInTOML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		keys   []interface{}
+		index  int
+		typ    reflect.Type
+		reason Reason
+	}{
+		{keys: []interface{}{"owner", "foo"}, index: 1, typ: reflect.TypeOf(map[string]interface{}{}), reason: ReasonKeyNotFound},
+		{keys: []interface{}{"owner", 1}, index: 1, typ: reflect.TypeOf(map[string]interface{}{}), reason: ReasonInvalidKey},
+		{keys: []interface{}{"hosts", 2}, index: 1, typ: reflect.TypeOf([]interface{}{}), reason: ReasonIndexOutOfRange},
+		{keys: []interface{}{"hosts", "alpha"}, index: 1, typ: reflect.TypeOf([]interface{}{}), reason: ReasonInvalidKey},
+		{keys: []interface{}{"friends", "id"}, index: 1, typ: reflect.TypeOf([]map[string]interface{}{}), reason: ReasonInvalidKey},
+		{keys: []interface{}{"friends", -1}, index: 1, typ: reflect.TypeOf([]map[string]interface{}{}), reason: ReasonIndexOutOfRange},
+		{keys: []interface{}{"title", "foo", "bar"}, index: 1, typ: reflect.TypeOf(""), reason: ReasonNotTraversable},
+	}
+	for i, test := range tests {
+		out, err := container.Lookup(test.keys...)
+		if out != nil {
+			t.Errorf("%d, expected nil container", i)
+		}
+		var pathErr *PathError
+		if !errors.As(err, &pathErr) {
+			t.Errorf("%d, expected path error, got %v", i, err)
+			continue
+		}
+		if pathErr.Index != test.index || pathErr.Type != test.typ || pathErr.Reason != test.reason {
+			t.Errorf("%d, expected index %d, type %v and reason %s, got %d, %v and %s",
+				i, test.index, test.typ, test.reason, pathErr.Index, pathErr.Type, pathErr.Reason)
+		}
+		if !reflect.DeepEqual(pathErr.Path, test.keys) {
+			t.Errorf("%d, expected path %v, got %v", i, test.keys, pathErr.Path)
+		}
+	}
+}
+
+func TestLookupFound(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"foo": []interface{}{nil}})
+	out, err := container.Lookup("foo", 0)
+	if err != nil || out == nil || out.Data() != nil {
+		t.Errorf("expected container with nil data, got %v (%v)", out, err)
+	}
+}
+
+func TestLookupOnNilContainer(t *testing.T) {
+	var container *Container
+	_, err := container.Lookup("foo")
+	var pathErr *PathError
+	if !errors.As(err, &pathErr) || pathErr.Reason != ReasonNilContainer {
+		t.Errorf("expected nil container path error, got %v", err)
+	}
+}
+
+func TestPathErrorMessage(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"items": []interface{}{1}})
+	_, err := container.Lookup("items", 3, "j")
+	expected := `solenodon: cannot get "items[3].j": index out of range at "items[3]"`
+	if err == nil || !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("expected error starting with %q, got %v", expected, err)
+	}
+}
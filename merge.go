@@ -0,0 +1,260 @@
+package solenodon
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ArrayStrategy determines how Merge combines two slices.
+type ArrayStrategy int
+
+const (
+	// ArrayReplace replaces our slice with theirs. This is the default.
+	ArrayReplace ArrayStrategy = iota
+	// ArrayAppend appends their elements to ours.
+	ArrayAppend
+	// ArrayMergeByIndex merges elements with the same index and appends the remaining elements of theirs.
+	ArrayMergeByIndex
+	// ArrayMergeByKey merges map elements that have the same value for the key field, see WithArrayMergeKey.
+	// Elements of theirs without a match are appended.
+	ArrayMergeByKey
+)
+
+// ConflictStrategy determines what Merge does when two different values are found at the same path,
+// and they are not both maps or both slices.
+type ConflictStrategy int
+
+const (
+	// ConflictTheirs keeps their value. This is the default.
+	ConflictTheirs ConflictStrategy = iota
+	// ConflictOurs keeps our value.
+	ConflictOurs
+	// ConflictError makes Merge return a *MergeConflictError without changing any data.
+	ConflictError
+)
+
+// NullStrategy determines what Merge does with a nil value in their data.
+type NullStrategy int
+
+const (
+	// NullOverwrite sets our value to nil. This is the default.
+	NullOverwrite NullStrategy = iota
+	// NullIgnore keeps our value.
+	NullIgnore
+	// NullDelete deletes the key from our map. A nil value that is not in a map is ignored.
+	NullDelete
+)
+
+// MergeOption configures Merge.
+type MergeOption func(*merger)
+
+// WithArrayStrategy sets how slices are merged.
+func WithArrayStrategy(strategy ArrayStrategy) MergeOption {
+	return func(m *merger) {
+		m.arrays = strategy
+	}
+}
+
+// WithArrayMergeKey merges slices of maps by the value of the given field, see ArrayMergeByKey.
+func WithArrayMergeKey(field string) MergeOption {
+	return func(m *merger) {
+		m.arrays = ArrayMergeByKey
+		m.mergeKey = field
+	}
+}
+
+// WithConflictStrategy sets how conflicting values are merged.
+func WithConflictStrategy(strategy ConflictStrategy) MergeOption {
+	return func(m *merger) {
+		m.conflicts = strategy
+	}
+}
+
+// WithNullStrategy sets how nil values in their data are merged.
+func WithNullStrategy(strategy NullStrategy) MergeOption {
+	return func(m *merger) {
+		m.nulls = strategy
+	}
+}
+
+// MergeConflictError is returned by Merge when the ConflictError strategy is used and two values conflict.
+type MergeConflictError struct {
+	// Path contains the keys, relative to the merged Containers, of the conflicting values.
+	Path   []interface{}
+	Ours   interface{}
+	Theirs interface{}
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("solenodon: merge conflict at %q: %v (%T) and %v (%T)",
+		FormatPath(e.Path...), e.Ours, e.Ours, e.Theirs, e.Theirs)
+}
+
+// Merge recursively merges the data of the other Container into this Container, copying its values.
+// Keys that are merged into a map[string]interface{} are formatted as strings.
+// How slices, conflicting values and nil values are merged is configured with the given options.
+func (c *Container) Merge(other *Container, opts ...MergeOption) error {
+	if c == nil {
+		return ErrNotFound
+	}
+	if other == nil {
+		return nil
+	}
+	m := &merger{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.conflicts == ConflictError {
+		if _, err := m.merge(c.data, other.data, nil, false); err != nil {
+			return err
+		}
+	}
+	data, err := m.merge(c.data, other.data, nil, true)
+	if err != nil {
+		return err
+	}
+	if c.SetData(data) == nil {
+		return errors.New("solenodon: cannot set merged data")
+	}
+	return nil
+}
+
+type merger struct {
+	arrays    ArrayStrategy
+	mergeKey  string
+	conflicts ConflictStrategy
+	nulls     NullStrategy
+}
+
+// merge returns the result of merging theirs into ours.
+// Our maps are modified in place if apply is true, and left untouched otherwise.
+func (m *merger) merge(ours, theirs interface{}, path []interface{}, apply bool) (interface{}, error) {
+	if theirs == nil {
+		if m.nulls == NullOverwrite {
+			return nil, nil
+		}
+		return ours, nil
+	}
+	if isMap(ours) && isMap(theirs) {
+		return m.mergeMaps(ours, theirs, path, apply)
+	}
+	if isSlice(ours) && isSlice(theirs) {
+		return m.mergeSlices(ours, theirs, path, apply)
+	}
+	if ours == nil || compareEqual(ours, true, theirs, true) {
+		return cloneData(theirs), nil
+	}
+	switch m.conflicts {
+	case ConflictOurs:
+		return ours, nil
+	case ConflictError:
+		return nil, &MergeConflictError{Path: append([]interface{}{}, path...), Ours: ours, Theirs: theirs}
+	}
+	return cloneData(theirs), nil
+}
+
+func (m *merger) mergeMaps(ours, theirs interface{}, path []interface{}, apply bool) (interface{}, error) {
+	container := NewContainer(ours)
+	theirsEntries, _ := mapEntries(theirs)
+	for _, entry := range theirsEntries {
+		key := entry.key
+		if _, ok := ours.(map[string]interface{}); ok {
+			if _, ok := key.(string); !ok {
+				key = fmt.Sprint(key)
+			}
+		}
+		existing, reason := child(ours, key)
+		if entry.value == nil && m.nulls == NullDelete {
+			if apply {
+				container.Delete(key)
+			}
+			continue
+		}
+		if reason != 0 {
+			if entry.value != nil || m.nulls == NullOverwrite {
+				if apply {
+					container.Set(cloneData(entry.value), key)
+				}
+			}
+			continue
+		}
+		value, err := m.merge(existing, entry.value, append(path, key), apply)
+		if err != nil {
+			return nil, err
+		}
+		if apply {
+			container.Get(key).SetData(value)
+		}
+	}
+	// Data that is not modified in place, such as a struct value, is replaced by a modified copy.
+	return container.data, nil
+}
+
+func (m *merger) mergeSlices(ours, theirs interface{}, path []interface{}, apply bool) (interface{}, error) {
+	oursElements, _ := sliceElements(ours)
+	theirsElements, _ := sliceElements(theirs)
+	var elements []interface{}
+	switch m.arrays {
+	case ArrayAppend:
+		elements = append([]interface{}{}, oursElements...)
+		for _, element := range theirsElements {
+			elements = append(elements, cloneData(element))
+		}
+	case ArrayMergeByIndex:
+		elements = append([]interface{}{}, oursElements...)
+		for i, element := range theirsElements {
+			if i >= len(elements) {
+				elements = append(elements, cloneData(element))
+				continue
+			}
+			value, err := m.merge(elements[i], element, append(path, i), apply)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = value
+		}
+	case ArrayMergeByKey:
+		elements = append([]interface{}{}, oursElements...)
+		for _, element := range theirsElements {
+			index := m.indexByKey(elements, element)
+			if index == -1 {
+				elements = append(elements, cloneData(element))
+				continue
+			}
+			value, err := m.merge(elements[index], element, append(path, index), apply)
+			if err != nil {
+				return nil, err
+			}
+			elements[index] = value
+		}
+	default:
+		return cloneData(theirs), nil
+	}
+	if slice, ok := sequenceLike(ours, elements); ok {
+		return slice, nil
+	}
+	return elements, nil
+}
+
+// indexByKey returns the index of the map element that has the same value for the merge key as the given element,
+// or -1 if there is none.
+func (m *merger) indexByKey(elements []interface{}, element interface{}) int {
+	value, reason := child(element, m.mergeKey)
+	if reason != 0 {
+		return -1
+	}
+	for i, e := range elements {
+		if v, reason := child(e, m.mergeKey); reason == 0 && compareEqual(v, true, value, true) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isMap(data interface{}) bool {
+	return shapeOf(data) == ShapeMap
+}
+
+func isSlice(data interface{}) bool {
+	return shapeOf(data) == ShapeSequence
+}
@@ -0,0 +1,160 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func newJSONContainer(t *testing.T, raw string) *Container {
+	t.Helper()
+	container, err := NewContainerFromBytes([]byte(raw), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return container
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		ours     string
+		theirs   string
+		opts     []MergeOption
+		expected string
+	}{
+		{
+			ours:     `{"a":1,"b":{"c":2,"d":3}}`,
+			theirs:   `{"b":{"c":4,"e":5},"f":6}`,
+			expected: `{"a":1,"b":{"c":4,"d":3,"e":5},"f":6}`,
+		},
+		{
+			ours:     `{"a":[1,2]}`,
+			theirs:   `{"a":[3]}`,
+			expected: `{"a":[3]}`,
+		},
+		{
+			ours:     `{"a":[1,2]}`,
+			theirs:   `{"a":[3]}`,
+			opts:     []MergeOption{WithArrayStrategy(ArrayAppend)},
+			expected: `{"a":[1,2,3]}`,
+		},
+		{
+			ours:     `{"a":[{"x":1},{"x":2}]}`,
+			theirs:   `{"a":[{"y":1},{"y":2},{"y":3}]}`,
+			opts:     []MergeOption{WithArrayStrategy(ArrayMergeByIndex)},
+			expected: `{"a":[{"x":1,"y":1},{"x":2,"y":2},{"y":3}]}`,
+		},
+		{
+			ours:     `{"a":[{"id":1,"x":1},{"id":2,"x":2}]}`,
+			theirs:   `{"a":[{"id":2,"x":3},{"id":4}]}`,
+			opts:     []MergeOption{WithArrayMergeKey("id")},
+			expected: `{"a":[{"id":1,"x":1},{"id":2,"x":3},{"id":4}]}`,
+		},
+		{
+			ours:     `{"a":1,"b":2}`,
+			theirs:   `{"a":3}`,
+			opts:     []MergeOption{WithConflictStrategy(ConflictOurs)},
+			expected: `{"a":1,"b":2}`,
+		},
+		{
+			ours:     `{"a":1,"b":2}`,
+			theirs:   `{"a":null,"c":null}`,
+			expected: `{"a":null,"b":2,"c":null}`,
+		},
+		{
+			ours:     `{"a":1,"b":2}`,
+			theirs:   `{"a":null,"c":null}`,
+			opts:     []MergeOption{WithNullStrategy(NullIgnore)},
+			expected: `{"a":1,"b":2}`,
+		},
+		{
+			ours:     `{"a":1,"b":2}`,
+			theirs:   `{"a":null,"c":null}`,
+			opts:     []MergeOption{WithNullStrategy(NullDelete)},
+			expected: `{"b":2}`,
+		},
+		{
+			ours:     `{"a":{"b":1}}`,
+			theirs:   `{"a":"foo"}`,
+			expected: `{"a":"foo"}`,
+		},
+	}
+	for i, test := range tests {
+		ours := newJSONContainer(t, test.ours)
+		theirs := newJSONContainer(t, test.theirs)
+		if err := ours.Merge(theirs, test.opts...); err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		expected := newJSONContainer(t, test.expected)
+		if !reflect.DeepEqual(ours.Data(), expected.Data()) {
+			t.Errorf("%d, expected %v, got %v", i, expected.Data(), ours.Data())
+		}
+	}
+}
+
+func TestMergeConflictError(t *testing.T) {
+	ours := newJSONContainer(t, `{"a":1,"b":{"c":2,"d":3}}`)
+	theirs := newJSONContainer(t, `{"a":1,"b":{"c":4},"e":5}`)
+	err := ours.Merge(theirs, WithConflictStrategy(ConflictError))
+	var conflictErr *MergeConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected merge conflict error, got %v", err)
+	}
+	if !reflect.DeepEqual(conflictErr.Path, []interface{}{"b", "c"}) {
+		t.Errorf("unexpected path %v", conflictErr.Path)
+	}
+	if ours.Has("e") {
+		t.Error("expected data to be untouched after a conflict")
+	}
+}
+
+func TestMergeMixedMapFlavors(t *testing.T) {
+	ours := newJSONContainer(t, `{"server":{"port":8080,"host":"localhost"},"1":"one"}`)
+	theirs, err := NewContainerFromBytes([]byte("server:\n  port: 8081\n1: uno\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ours.Merge(theirs, WithConflictStrategy(ConflictError)); err == nil {
+		t.Error("expected conflict between 8080 and 8081")
+	}
+	if err := ours.Merge(theirs); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"server": map[string]interface{}{"port": 8081, "host": "localhost"},
+		"1":      "uno",
+	}
+	if !reflect.DeepEqual(ours.Data(), expected) {
+		t.Errorf("expected %v, got %v", expected, ours.Data())
+	}
+	theirs.Get("server").SetData("changed")
+	if ours.Get("server", "port").Data() != 8081 {
+		t.Error("expected merged data not to be shared")
+	}
+}
+
+func TestMergeEqualNumbersDoNotConflict(t *testing.T) {
+	ours := newJSONContainer(t, `{"port":8080}`)
+	theirs := NewContainer(map[interface{}]interface{}{"port": 8080})
+	if err := ours.Merge(theirs, WithConflictStrategy(ConflictError)); err != nil {
+		t.Errorf("unexpected error '%s'", err)
+	}
+}
+
+func TestMergeIntoStructValue(t *testing.T) {
+	type S struct {
+		A int
+		B string
+	}
+	ours := NewContainer(map[string]interface{}{"s": S{A: 1, B: "b"}})
+	if err := ours.Merge(NewContainer(map[string]interface{}{"s": map[string]interface{}{"A": 5}})); err != nil {
+		t.Fatal(err)
+	}
+	if data := ours.Get("s").Data(); data != (S{A: 5, B: "b"}) {
+		t.Errorf("expected merged struct, got %#v", data)
+	}
+}
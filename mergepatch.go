@@ -0,0 +1,93 @@
+package solenodon
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ApplyMergePatch applies the given JSON Merge Patch to the data in the Container, as described by RFC 7386.
+// Values of the patch are copied.
+func (c *Container) ApplyMergePatch(patch *Container) error {
+	if c == nil {
+		return ErrNotFound
+	}
+	if !isMap(patch.Data()) {
+		if c.SetData(cloneData(patch.Data())) == nil {
+			return errors.New("solenodon: cannot set merge patch data")
+		}
+		return nil
+	}
+	if !isMap(c.data) && c.SetData(c.newNode("")) == nil {
+		return errors.New("solenodon: cannot set merge patch data")
+	}
+	entries, _ := mapEntries(patch.data)
+	for _, entry := range entries {
+		key := entry.key
+		if _, ok := c.data.(map[string]interface{}); ok {
+			if _, ok := key.(string); !ok {
+				key = fmt.Sprint(key)
+			}
+		}
+		if entry.value == nil {
+			c.Delete(key)
+			continue
+		}
+		target := c.Get(key)
+		if target == nil {
+			target = c.Set(nil, key)
+		}
+		if err := target.ApplyMergePatch(NewContainer(entry.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateMergePatch returns a JSON Merge Patch, as described by RFC 7386, that turns the data of from into that of to.
+// Since a nil value deletes a key, keys whose value changes to nil are deleted by the patch.
+func CreateMergePatch(from, to *Container) *Container {
+	return NewContainer(createMergePatch(from.Data(), to.Data()))
+}
+
+func createMergePatch(from, to interface{}) interface{} {
+	if !isMap(from) || !isMap(to) {
+		return cloneData(to)
+	}
+	patch := map[interface{}]interface{}{}
+	fromEntries, _ := mapEntries(from)
+	for _, entry := range fromEntries {
+		if _, reason := child(to, entry.key); reason != 0 {
+			patch[entry.key] = nil
+		}
+	}
+	toEntries, _ := mapEntries(to)
+	for _, entry := range toEntries {
+		value, reason := child(from, entry.key)
+		switch {
+		case reason != 0:
+			patch[entry.key] = cloneData(entry.value)
+		case isMap(value) && isMap(entry.value):
+			if sub := createMergePatch(value, entry.value); len(childKeys(sub)) > 0 {
+				patch[entry.key] = sub
+			}
+		case !compareEqual(value, true, entry.value, true):
+			patch[entry.key] = cloneData(entry.value)
+		}
+	}
+	return mergePatchMap(from, to, patch)
+}
+
+// mergePatchMap returns the patch as a map[interface{}]interface{} if from or to is one, so that keys keep their type.
+// Otherwise the keys are formatted as strings.
+func mergePatchMap(from, to interface{}, patch map[interface{}]interface{}) interface{} {
+	_, fromInterface := from.(map[interface{}]interface{})
+	_, toInterface := to.(map[interface{}]interface{})
+	if fromInterface || toInterface {
+		return patch
+	}
+	m := make(map[string]interface{}, len(patch))
+	for key, value := range patch {
+		m[fmt.Sprint(key)] = value
+	}
+	return m
+}
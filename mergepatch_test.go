@@ -0,0 +1,118 @@
+package solenodon
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergePatchTests are the test cases from appendix A of RFC 7386.
+var mergePatchTests = []struct {
+	original string
+	patch    string
+	result   string
+}{
+	{original: `{"a":"b"}`, patch: `{"a":"c"}`, result: `{"a":"c"}`},
+	{original: `{"a":"b"}`, patch: `{"b":"c"}`, result: `{"a":"b","b":"c"}`},
+	{original: `{"a":"b"}`, patch: `{"a":null}`, result: `{}`},
+	{original: `{"a":"b","b":"c"}`, patch: `{"a":null}`, result: `{"b":"c"}`},
+	{original: `{"a":["b"]}`, patch: `{"a":"c"}`, result: `{"a":"c"}`},
+	{original: `{"a":"c"}`, patch: `{"a":["b"]}`, result: `{"a":["b"]}`},
+	{original: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, result: `{"a":{"b":"d"}}`},
+	{original: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, result: `{"a":[1]}`},
+	{original: `["a","b"]`, patch: `["c","d"]`, result: `["c","d"]`},
+	{original: `{"a":"b"}`, patch: `["c"]`, result: `["c"]`},
+	{original: `{"a":"foo"}`, patch: `null`, result: `null`},
+	{original: `{"a":"foo"}`, patch: `"bar"`, result: `"bar"`},
+	{original: `{"e":null}`, patch: `{"a":1}`, result: `{"e":null,"a":1}`},
+	{original: `[1,2]`, patch: `{"a":"b","c":null}`, result: `{"a":"b"}`},
+	{original: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, result: `{"a":{"bb":{}}}`},
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	for i, test := range mergePatchTests {
+		container := newJSONContainer(t, test.original)
+		if err := container.ApplyMergePatch(newJSONContainer(t, test.patch)); err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		expected := newJSONContainer(t, test.result)
+		if !reflect.DeepEqual(container.Data(), expected.Data()) {
+			t.Errorf("%d, expected %v, got %v", i, expected.Data(), container.Data())
+		}
+	}
+}
+
+func TestApplyMergePatchToChild(t *testing.T) {
+	container := newJSONContainer(t, `{"a":{"b":"c"},"d":1}`)
+	if err := container.Get("a").ApplyMergePatch(newJSONContainer(t, `"e"`)); err != nil {
+		t.Fatal(err)
+	}
+	expected := newJSONContainer(t, `{"a":"e","d":1}`)
+	if !reflect.DeepEqual(container.Data(), expected.Data()) {
+		t.Errorf("expected %v, got %v", expected.Data(), container.Data())
+	}
+}
+
+func TestApplyMergePatchToYAML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte("a:\n  1: one\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := container.ApplyMergePatch(newJSONContainer(t, `{"a":{"b":{"c":true}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := container.Get("a", "b").Data().(map[interface{}]interface{}); !ok {
+		t.Errorf("expected new map to match the flavor of its parent, got %T", container.Get("a", "b").Data())
+	}
+	if container.Get("a", 1).Data() != "one" || container.Get("a", "b", "c").Data() != true {
+		t.Errorf("unexpected data %v", container.Data())
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	for i, test := range mergePatchTests {
+		from := newJSONContainer(t, test.original)
+		to := newJSONContainer(t, test.result)
+		patch := CreateMergePatch(from, to)
+		if err := from.ApplyMergePatch(patch); err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(from.Data(), to.Data()) {
+			t.Errorf("%d, expected %v after applying %v, got %v", i, to.Data(), patch.Data(), from.Data())
+		}
+	}
+}
+
+func TestCreateMergePatchIsMinimal(t *testing.T) {
+	from := newJSONContainer(t, `{"a":{"b":1,"c":2},"d":[1],"e":3}`)
+	to := newJSONContainer(t, `{"a":{"b":1,"c":3},"d":[1],"f":4}`)
+	expected := newJSONContainer(t, `{"a":{"c":3},"e":null,"f":4}`)
+	if patch := CreateMergePatch(from, to); !reflect.DeepEqual(patch.Data(), expected.Data()) {
+		t.Errorf("expected %v, got %v", expected.Data(), patch.Data())
+	}
+}
+
+func TestCreateMergePatchKeepsYAMLKeys(t *testing.T) {
+	from, err := NewContainerFromBytes([]byte("1: one\n2: two\nname: x\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := NewContainerFromBytes([]byte("2: three\nname: x\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch := CreateMergePatch(from, to)
+	expected := map[interface{}]interface{}{1: nil, 2: "three"}
+	if !reflect.DeepEqual(patch.Data(), expected) {
+		t.Errorf("expected %v, got %v", expected, patch.Data())
+	}
+	if err := from.ApplyMergePatch(patch); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(from.Data(), to.Data()) {
+		t.Errorf("expected %v, got %v", to.Data(), from.Data())
+	}
+}
@@ -0,0 +1,225 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PatchOp is an operation of a JSON Patch, as described by RFC 6902.
+// A JSON Patch document can be unmarshaled into a []PatchOp with encoding/json.
+type PatchOp struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" or "test".
+	Op string `json:"op"`
+	// Path is a JSON Pointer to the target location.
+	Path string `json:"path"`
+	// From is a JSON Pointer to the source location of a "move" or "copy" operation.
+	From string `json:"from,omitempty"`
+	// Value is the value of an "add", "replace" or "test" operation.
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON leaves out the value of "remove", "move" and "copy" operations.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	type patchOp PatchOp
+	switch op.Op {
+	case "remove", "move", "copy":
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+			From string `json:"from,omitempty"`
+		}{Op: op.Op, Path: op.Path, From: op.From})
+	}
+	return json.Marshal(patchOp(op))
+}
+
+// UnmarshalJSON rejects "add", "replace" and "test" operations without a value.
+// A null value is kept as nil.
+func (op *PatchOp) UnmarshalJSON(b []byte) error {
+	type patchOp PatchOp
+	var raw struct {
+		patchOp
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*op = PatchOp(raw.patchOp)
+	if raw.Value == nil {
+		switch op.Op {
+		case "add", "replace", "test":
+			return fmt.Errorf("solenodon: patch operation %q at %q has no value", op.Op, op.Path)
+		}
+		return nil
+	}
+	return json.Unmarshal(raw.Value, &op.Value)
+}
+
+// PatchError is returned by ApplyPatch when an operation fails.
+type PatchError struct {
+	// Index is the index of the failed operation.
+	Index int
+	Op    PatchOp
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("solenodon: patch operation %d (%s %q) failed: %s", e.Index, e.Op.Op, e.Op.Path, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPatch applies the given JSON Patch operations to the data in the Container, as described by RFC 6902.
+// If any operation fails, including a "test" operation, the data is left unchanged and a *PatchError is returned.
+// Data with a registered NodeAdapter can only be patched if the adapter implements CloneAdapter.
+func (c *Container) ApplyPatch(ops []PatchOp) error {
+	if c == nil {
+		return ErrNotFound
+	}
+	// The operations are applied to a copy, which is only written back if all operations succeed.
+	data, ok := cloneDetached(c.data)
+	if !ok {
+		return errors.New("solenodon: cannot patch data with a NodeAdapter that does not implement CloneAdapter")
+	}
+	doc := NewContainer(data)
+	if err := applyPatch(doc, ops); err != nil {
+		return err
+	}
+	if c.SetData(doc.data) == nil {
+		return errors.New("solenodon: cannot set patched data")
+	}
+	return nil
+}
+
+func applyPatch(doc *Container, ops []PatchOp) error {
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case "add":
+			err = patchAdd(doc, op.Path, cloneData(op.Value))
+		case "remove":
+			_, err = patchRemove(doc, op.Path)
+		case "replace":
+			err = patchReplace(doc, op.Path, cloneData(op.Value))
+		case "move":
+			err = patchMove(doc, op.From, op.Path)
+		case "copy":
+			var from *Container
+			if from, err = patchGet(doc, op.From); err == nil {
+				err = patchAdd(doc, op.Path, cloneData(from.data))
+			}
+		case "test":
+			var target *Container
+			if target, err = patchGet(doc, op.Path); err == nil && !compareEqual(target.data, true, op.Value, true) {
+				err = fmt.Errorf("value %v (%T) is not equal to %v (%T)", target.data, target.data, op.Value, op.Value)
+			}
+		default:
+			err = fmt.Errorf("unknown operation %q", op.Op)
+		}
+		if err != nil {
+			return &PatchError{Index: i, Op: op, Err: err}
+		}
+	}
+	return nil
+}
+
+// patchTarget returns the Container of the parent of the location referenced by the pointer,
+// and the key of the location in that parent. The parent is nil if the pointer refers to the whole document.
+func patchTarget(doc *Container, pointer string) (*Container, interface{}, error) {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, nil
+	}
+	keys, ok := doc.pointerKeys(tokens[:len(tokens)-1])
+	if !ok {
+		return nil, nil, fmt.Errorf("path %q not found", pointer)
+	}
+	parent := doc.Get(keys...)
+	if parent == nil {
+		return nil, nil, fmt.Errorf("path %q not found", pointer)
+	}
+	keys, ok = parent.pointerKeys(tokens[len(tokens)-1:])
+	if !ok {
+		return nil, nil, fmt.Errorf("path %q not found", pointer)
+	}
+	return parent, keys[0], nil
+}
+
+func patchGet(doc *Container, pointer string) (*Container, error) {
+	parent, key, err := patchTarget(doc, pointer)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return doc, nil
+	}
+	target := parent.Get(key)
+	if target == nil {
+		return nil, fmt.Errorf("path %q not found", pointer)
+	}
+	return target, nil
+}
+
+func patchAdd(doc *Container, pointer string, value interface{}) error {
+	parent, key, err := patchTarget(doc, pointer)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		doc.SetData(value)
+		return nil
+	}
+	if isSlice(parent.data) {
+		if parent.Insert(key.(int), value) == nil {
+			return fmt.Errorf("index of path %q out of range", pointer)
+		}
+		return nil
+	}
+	if parent.Set(value, key) == nil {
+		return fmt.Errorf("cannot add value at path %q", pointer)
+	}
+	return nil
+}
+
+func patchRemove(doc *Container, pointer string) (interface{}, error) {
+	target, err := patchGet(doc, pointer)
+	if err != nil {
+		return nil, err
+	}
+	if target.parent == nil {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	target.parent.Delete(target.key)
+	return target.data, nil
+}
+
+func patchReplace(doc *Container, pointer string, value interface{}) error {
+	target, err := patchGet(doc, pointer)
+	if err != nil {
+		return err
+	}
+	if target.SetData(value) == nil {
+		return fmt.Errorf("cannot replace value at path %q", pointer)
+	}
+	return nil
+}
+
+func patchMove(doc *Container, from, pointer string) error {
+	if from == pointer {
+		_, err := patchGet(doc, from)
+		return err
+	}
+	if len(pointer) > len(from) && pointer[:len(from)] == from && pointer[len(from)] == '/' {
+		return fmt.Errorf("cannot move %q into one of its children", from)
+	}
+	value, err := patchRemove(doc, from)
+	if err != nil {
+		return err
+	}
+	return patchAdd(doc, pointer, value)
+}
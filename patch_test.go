@@ -0,0 +1,183 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func parsePatch(t *testing.T, raw string) []PatchOp {
+	t.Helper()
+	var ops []PatchOp
+	if err := json.Unmarshal([]byte(raw), &ops); err != nil {
+		t.Fatal(err)
+	}
+	return ops
+}
+
+// Most of the test cases are taken from appendix A of RFC 6902.
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expected: `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			doc:      `{"foo":["bar","baz"]}`,
+			patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expected: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"remove","path":"/baz"}]`,
+			expected: `{"foo":"bar"}`,
+		},
+		{
+			doc:      `{"foo":["bar","qux","baz"]}`,
+			patch:    `[{"op":"remove","path":"/foo/1"}]`,
+			expected: `{"foo":["bar","baz"]}`,
+		},
+		{
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			doc:      `{"foo":["all","grass","cows","eat"]}`,
+			patch:    `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			expected: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			doc:      `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch:    `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			expected: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			expected: `{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			doc:      `{"foo":["bar"]}`,
+			patch:    `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			expected: `{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			doc:      `{"foo":{"bar":1}}`,
+			patch:    `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			expected: `{"foo":{"bar":1},"baz":{"bar":2}}`,
+		},
+		{
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"","value":[1]}]`,
+			expected: `[1]`,
+		},
+		{
+			doc:      `{"/":9,"~1":10}`,
+			patch:    `[{"op":"test","path":"/~01","value":10}]`,
+			expected: `{"/":9,"~1":10}`,
+		},
+	}
+	for i, test := range tests {
+		container := newJSONContainer(t, test.doc)
+		if err := container.ApplyPatch(parsePatch(t, test.patch)); err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		expected := newJSONContainer(t, test.expected)
+		if !reflect.DeepEqual(container.Data(), expected.Data()) {
+			t.Errorf("%d, expected %v, got %v", i, expected.Data(), container.Data())
+		}
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		index int
+	}{
+		{doc: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`, index: 0},
+		{doc: `{"foo":"bar"}`, patch: `[{"op":"remove","path":"/foo"},{"op":"remove","path":"/foo"}]`, index: 1},
+		{doc: `{"foo":"bar"}`, patch: `[{"op":"replace","path":"/baz","value":1}]`, index: 0},
+		{doc: `{"foo":[1]}`, patch: `[{"op":"add","path":"/foo/2","value":1}]`, index: 0},
+		{doc: `{"foo":[1]}`, patch: `[{"op":"add","path":"/foo/01","value":1}]`, index: 0},
+		{doc: `{"foo":{"a":1}}`, patch: `[{"op":"move","from":"/foo","path":"/foo/a/b"}]`, index: 0},
+		{doc: `{"baz":"qux"}`, patch: `[{"op":"add","path":"/x","value":1},{"op":"test","path":"/baz","value":"bar"}]`, index: 1},
+		{doc: `{"baz":"qux"}`, patch: `[{"op":"copy","from":"/missing","path":"/x"}]`, index: 0},
+		{doc: `{"baz":"qux"}`, patch: `[{"op":"foo","path":"/x"}]`, index: 0},
+		{doc: `{"baz":"qux"}`, patch: `[{"op":"add","path":"x","value":1}]`, index: 0},
+	}
+	for i, test := range tests {
+		container := newJSONContainer(t, test.doc)
+		err := container.ApplyPatch(parsePatch(t, test.patch))
+		var patchErr *PatchError
+		if !errors.As(err, &patchErr) {
+			t.Errorf("%d, expected patch error, got %v", i, err)
+			continue
+		}
+		if patchErr.Index != test.index {
+			t.Errorf("%d, expected failure at operation %d, got %d", i, test.index, patchErr.Index)
+		}
+		expected := newJSONContainer(t, test.doc)
+		if !reflect.DeepEqual(container.Data(), expected.Data()) {
+			t.Errorf("%d, expected data to be unchanged, got %v", i, container.Data())
+		}
+	}
+}
+
+func TestUnmarshalPatchValue(t *testing.T) {
+	tests := []struct {
+		raw   string
+		valid bool
+	}{
+		{raw: `{"op":"add","path":"/a","value":null}`, valid: true},
+		{raw: `{"op":"test","path":"/a","value":0}`, valid: true},
+		{raw: `{"op":"remove","path":"/a"}`, valid: true},
+		{raw: `{"op":"add","path":"/a"}`, valid: false},
+		{raw: `{"op":"replace","path":"/a"}`, valid: false},
+		{raw: `{"op":"test","path":"/a"}`, valid: false},
+	}
+	for i, test := range tests {
+		var op PatchOp
+		err := json.Unmarshal([]byte(test.raw), &op)
+		if (err == nil) != test.valid {
+			t.Errorf("%d, expected valid %v, got error %v", i, test.valid, err)
+		}
+	}
+}
+
+func TestApplyPatchToTOMLChild(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	friends := container.Get("friends")
+	ops := []PatchOp{
+		{Op: "remove", Path: "/0"},
+		{Op: "add", Path: "/-", Value: map[string]interface{}{"id": int64(3)}},
+		{Op: "test", Path: "/2/id", Value: 3.0},
+	}
+	if err := friends.ApplyPatch(ops); err != nil {
+		t.Fatal(err)
+	}
+	if data := container.Get("friends", 2, "id").Data(); data != int64(3) {
+		t.Errorf("expected 3, got %v", data)
+	}
+	if data := container.Get("friends", 0, "name").Data(); data != "Nina Andrews" {
+		t.Errorf("expected Nina Andrews, got %v", data)
+	}
+}
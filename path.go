@@ -0,0 +1,165 @@
+package solenodon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError is returned when a path expression cannot be parsed.
+type SyntaxError struct {
+	Input  string
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("solenodon: syntax error in %q at offset %d: %s", e.Input, e.Offset, e.Msg)
+}
+
+// ParsePath parses a path such as "items[2].j" into the keys that Get expects.
+// Brackets hold a non-negative int or a double-quoted string, e.g. `[""]` is the empty key.
+// A backslash escapes the character that follows it, e.g. `a\.b` is the single key "a.b".
+func ParsePath(path string) ([]interface{}, error) {
+	keys := []interface{}{}
+	i := 0
+	expectKey := true
+	for i < len(path) {
+		switch path[i] {
+		case '.':
+			if expectKey {
+				return nil, &SyntaxError{Input: path, Offset: i, Msg: "empty key"}
+			}
+			i++
+			if i == len(path) {
+				return nil, &SyntaxError{Input: path, Offset: i, Msg: "empty key"}
+			}
+			expectKey = true
+		case '[':
+			if expectKey && i > 0 {
+				return nil, &SyntaxError{Input: path, Offset: i, Msg: "unexpected '[' after '.'"}
+			}
+			if strings.HasPrefix(path[i+1:], `"`) {
+				quoted, err := strconv.QuotedPrefix(path[i+1:])
+				if err != nil {
+					return nil, &SyntaxError{Input: path, Offset: i + 1, Msg: "invalid quoted key"}
+				}
+				end := i + 1 + len(quoted)
+				if end == len(path) || path[end] != ']' {
+					return nil, &SyntaxError{Input: path, Offset: end, Msg: "missing ']'"}
+				}
+				key, _ := strconv.Unquote(quoted)
+				keys = append(keys, key)
+				i = end + 1
+				expectKey = false
+				continue
+			}
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, &SyntaxError{Input: path, Offset: i, Msg: "missing ']'"}
+			}
+			digits := path[i+1 : i+end]
+			index, err := strconv.Atoi(digits)
+			if err != nil || strings.TrimLeft(digits, "0123456789") != "" {
+				return nil, &SyntaxError{Input: path, Offset: i + 1, Msg: fmt.Sprintf("invalid index %q", digits)}
+			}
+			keys = append(keys, index)
+			i += end + 1
+			expectKey = false
+		case ']':
+			return nil, &SyntaxError{Input: path, Offset: i, Msg: "unexpected ']'"}
+		default:
+			if !expectKey {
+				return nil, &SyntaxError{Input: path, Offset: i, Msg: "expected '.' or '['"}
+			}
+			var key strings.Builder
+		segment:
+			for ; i < len(path); i++ {
+				switch path[i] {
+				case '\\':
+					i++
+					if i == len(path) {
+						return nil, &SyntaxError{Input: path, Offset: i - 1, Msg: "trailing backslash"}
+					}
+					key.WriteByte(path[i])
+				case '.', '[':
+					break segment
+				case ']':
+					return nil, &SyntaxError{Input: path, Offset: i, Msg: "unexpected ']'"}
+				default:
+					key.WriteByte(path[i])
+				}
+			}
+			keys = append(keys, key.String())
+			expectKey = false
+		}
+	}
+	return keys, nil
+}
+
+// FormatPath formats the given keys as a path that can be parsed by ParsePath.
+// An Index is formatted like an int, although ParsePath rejects negative indices.
+// The empty key is formatted as `[""]`. Keys that are neither a string, an int nor an Index are formatted as a string.
+func FormatPath(keys ...interface{}) string {
+	var b strings.Builder
+	for i, key := range keys {
+		if v, ok := key.(Index); ok {
+			key = int(v)
+		}
+		if v, ok := key.(int); ok {
+			b.WriteString("[" + strconv.Itoa(v) + "]")
+			continue
+		}
+		if key == "" {
+			b.WriteString(`[""]`)
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		s, ok := key.(string)
+		if !ok {
+			s = fmt.Sprint(key)
+		}
+		for j := 0; j < len(s); j++ {
+			switch s[j] {
+			case '\\', '.', '[', ']':
+				b.WriteByte('\\')
+			}
+			b.WriteByte(s[j])
+		}
+	}
+	return b.String()
+}
+
+// GetPath returns a Container containing the value at the given path.
+// See ParsePath for the path syntax.
+// The returned container will be nil if the path is invalid or no result was found.
+func (c *Container) GetPath(path string) *Container {
+	keys, err := ParsePath(path)
+	if err != nil {
+		return nil
+	}
+	return c.Get(keys...)
+}
+
+// HasPath returns true if the Container has a value at the given path.
+func (c *Container) HasPath(path string) bool {
+	return c.GetPath(path) != nil
+}
+
+// DeletePath deletes the value, if any, at the given path.
+// The Container on which this method is called will be returned.
+func (c *Container) DeletePath(path string) *Container {
+	keys, err := ParsePath(path)
+	if err != nil {
+		return c
+	}
+	return c.Delete(keys...)
+}
+
+// SetPath sets the given data at the given path.
+// The Container at the path will be returned, or nil if the data could not be set.
+func (c *Container) SetPath(data interface{}, path string) *Container {
+	return c.GetPath(path).SetData(data)
+}
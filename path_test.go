@@ -0,0 +1,70 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		keys []interface{}
+	}{
+		{path: "", keys: []interface{}{}},
+		{path: "foo", keys: []interface{}{"foo"}},
+		{path: "items[2].j", keys: []interface{}{"items", 2, "j"}},
+		{path: "[0][1]", keys: []interface{}{0, 1}},
+		{path: `a\.b.c`, keys: []interface{}{"a.b", "c"}},
+		{path: `a\[0\]`, keys: []interface{}{"a[0]"}},
+		{path: `a\\`, keys: []interface{}{`a\`}},
+		{path: `[""]`, keys: []interface{}{""}},
+		{path: `a[""].b`, keys: []interface{}{"a", "", "b"}},
+		{path: `[""][""]`, keys: []interface{}{"", ""}},
+	}
+	for i, test := range tests {
+		keys, err := ParsePath(test.path)
+		if err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(keys, test.keys) {
+			t.Errorf("%d, expected keys %#v, got %#v", i, test.keys, keys)
+		}
+		if out := FormatPath(keys...); out != test.path {
+			t.Errorf("%d, expected formatted path %q, got %q", i, test.path, out)
+		}
+	}
+}
+
+func TestParsePathInvalid(t *testing.T) {
+	paths := []string{".", "a.", "a..b", "a[", "a[]", "a[x]", "a[-1]", "a[-0]", "a[+0]", "a[ 1]", "a[\"]", `a["x"`, "a]", "a[0]b", "a.[0]", `a.[""]`, `a\`}
+	for i, path := range paths {
+		if _, err := ParsePath(path); err == nil {
+			t.Errorf("%d, expected error for path %q", i, path)
+		}
+	}
+}
+
+func TestPathMethods(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(`{"a.b":{"items":[1,{"j":7}]}}`), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !container.HasPath(`a\.b.items[1].j`) {
+		t.Error("expected container to have path")
+	}
+	if container.GetPath("a[") != nil {
+		t.Error("expected nil container for invalid path")
+	}
+	if container.SetPath(44, `a\.b.items[1].j`) == nil {
+		t.Error("unexpected nil container after SetPath")
+	}
+	if data := container.Get("a.b", "items", 1, "j").Data(); data != 44 {
+		t.Errorf("expected 44, got %v", data)
+	}
+	container.DeletePath(`a\.b.items[0]`)
+	if data := container.GetPath(`a\.b.items`).Data(); len(data.([]interface{})) != 1 {
+		t.Errorf("expected one item after DeletePath, got %v", data)
+	}
+}
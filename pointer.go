@@ -0,0 +1,146 @@
+package solenodon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePointer parses a JSON Pointer as defined by RFC 6901 into its unescaped reference tokens.
+// The empty pointer refers to the whole document and results in no tokens.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != '/' {
+		return nil, &SyntaxError{Input: pointer, Offset: 0, Msg: "pointer must start with '/'"}
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	offset := 1
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				continue
+			}
+			if j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, &SyntaxError{Input: pointer, Offset: offset + j, Msg: "invalid escape sequence"}
+			}
+		}
+		offset += len(token) + 1
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// FormatPointer formats the given keys as a JSON Pointer.
+// Keys that are neither a string nor an int are formatted as a string.
+func FormatPointer(keys ...interface{}) string {
+	var b strings.Builder
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, key := range keys {
+		b.WriteByte('/')
+		switch v := key.(type) {
+		case string:
+			b.WriteString(escaper.Replace(v))
+		case int:
+			b.WriteString(strconv.Itoa(v))
+		default:
+			b.WriteString(escaper.Replace(fmt.Sprint(v)))
+		}
+	}
+	return b.String()
+}
+
+// pointerKeys resolves the given reference tokens against the data in the Container into the keys that Get expects.
+// The token "-" of an array results in the index after its last element.
+func (c *Container) pointerKeys(tokens []string) ([]interface{}, bool) {
+	keys := make([]interface{}, 0, len(tokens))
+	data := c.Data()
+	for _, token := range tokens {
+		var key interface{}
+		switch shapeOf(data) {
+		case ShapeMap:
+			key = token
+			if _, reason := child(data, token); reason != 0 {
+				if index, ok := arrayIndex(token); ok {
+					if _, reason := child(data, index); reason == 0 {
+						key = index
+					}
+				}
+			}
+		case ShapeSequence:
+			length, _ := arrayLength(data)
+			index, ok := arrayIndexOrEnd(token, length)
+			if !ok {
+				return nil, false
+			}
+			key = index
+		default:
+			return nil, false
+		}
+		data, _ = child(data, key)
+		keys = append(keys, key)
+	}
+	return keys, true
+}
+
+// arrayIndex returns the array index for the given reference token.
+// Leading zeros are not allowed, as described by RFC 6901.
+func arrayIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return 0, false
+		}
+	}
+	index, err := strconv.Atoi(token)
+	return index, err == nil
+}
+
+func arrayIndexOrEnd(token string, length int) (int, bool) {
+	if token == "-" {
+		return length, true
+	}
+	return arrayIndex(token)
+}
+
+// GetPointer returns a Container containing the value referenced by the given JSON Pointer.
+// The returned container will be nil if the pointer is invalid or no result was found.
+func (c *Container) GetPointer(pointer string) *Container {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return nil
+	}
+	keys, ok := c.pointerKeys(tokens)
+	if !ok {
+		return nil
+	}
+	return c.Get(keys...)
+}
+
+// HasPointer returns true if the Container has a value referenced by the given JSON Pointer.
+func (c *Container) HasPointer(pointer string) bool {
+	return c.GetPointer(pointer) != nil
+}
+
+// DeletePointer deletes the value, if any, referenced by the given JSON Pointer.
+// The Container on which this method is called will be returned.
+func (c *Container) DeletePointer(pointer string) *Container {
+	tokens, err := ParsePointer(pointer)
+	if err != nil {
+		return c
+	}
+	keys, ok := c.pointerKeys(tokens)
+	if !ok {
+		return c
+	}
+	return c.Delete(keys...)
+}
+
+// SetPointer sets the given data at the value referenced by the given JSON Pointer.
+// The Container of the value will be returned, or nil if the data could not be set.
+func (c *Container) SetPointer(pointer string, data interface{}) *Container {
+	return c.GetPointer(pointer).SetData(data)
+}
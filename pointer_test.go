@@ -0,0 +1,117 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		pointer string
+		tokens  []string
+	}{
+		{pointer: "", tokens: []string{}},
+		{pointer: "/", tokens: []string{""}},
+		{pointer: "/foo/0", tokens: []string{"foo", "0"}},
+		{pointer: "/a~1b/m~0n", tokens: []string{"a/b", "m~n"}},
+		{pointer: "/~01", tokens: []string{"~1"}},
+	}
+	for i, test := range tests {
+		tokens, err := ParsePointer(test.pointer)
+		if err != nil {
+			t.Errorf("%d, unexpected error '%s'", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(tokens, test.tokens) {
+			t.Errorf("%d, expected tokens %#v, got %#v", i, test.tokens, tokens)
+		}
+	}
+}
+
+func TestParsePointerInvalid(t *testing.T) {
+	pointers := []string{"foo", "/~", "/~2", "/a~"}
+	for i, pointer := range pointers {
+		if _, err := ParsePointer(pointer); err == nil {
+			t.Errorf("%d, expected error for pointer %q", i, pointer)
+		}
+	}
+}
+
+func TestFormatPointer(t *testing.T) {
+	if out := FormatPointer("a/b", 2, "m~n"); out != "/a~1b/2/m~0n" {
+		t.Errorf("unexpected pointer %q", out)
+	}
+}
+
+func TestGetPointerInJSON(t *testing.T) {
+	raw := `{"foo":["bar","baz"],"":0,"a/b":1,"m~n":8,"items":[2,3,{"i":6,"j":7}]}`
+	container, err := NewContainerFromBytes([]byte(raw), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pointer string
+		dataOut interface{}
+		nilOut  bool
+	}{
+		{pointer: "/foo/0", dataOut: "bar"},
+		{pointer: "/", dataOut: 0.0},
+		{pointer: "/a~1b", dataOut: 1.0},
+		{pointer: "/m~0n", dataOut: 8.0},
+		{pointer: "/items/2/j", dataOut: 7.0},
+		{pointer: "/items/-", nilOut: true},
+		{pointer: "/items/01", nilOut: true},
+		{pointer: "/items/x", nilOut: true},
+		{pointer: "/foo/0/bar", nilOut: true},
+		{pointer: "/missing/0", nilOut: true},
+	}
+	for i, test := range tests {
+		out := container.GetPointer(test.pointer)
+		if out == nil {
+			if !test.nilOut {
+				t.Errorf("%d, unexpected nil container", i)
+			}
+		} else if test.nilOut {
+			t.Errorf("%d, expected nil container, got '%v'", i, out.Data())
+		} else if out.Data() != test.dataOut {
+			t.Errorf("%d, expected data '%v' (%T), got '%v' (%T)", i, test.dataOut, test.dataOut, out.Data(), out.Data())
+		}
+	}
+}
+
+func TestGetPointerInYAML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte("foo:\n  1: one\n  bar: [a, b]\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := container.GetPointer("/foo/1").Data(); data != "one" {
+		t.Errorf("expected 'one', got '%v'", data)
+	}
+	if data := container.GetPointer("/foo/bar/1").Data(); data != "b" {
+		t.Errorf("expected 'b', got '%v'", data)
+	}
+}
+
+func TestPointerMethodsInTOML(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !container.HasPointer("/friends/1/name") {
+		t.Error("expected container to have pointer")
+	}
+	if container.SetPointer("/friends/1/name", "Big Bob") == nil {
+		t.Error("unexpected nil container after SetPointer")
+	}
+	if data := container.Get("friends", 1, "name").Data(); data != "Big Bob" {
+		t.Errorf("expected 'Big Bob', got '%v'", data)
+	}
+	container.DeletePointer("/friends/0")
+	if data := container.GetPointer("/friends/0/name").Data(); data != "Big Bob" {
+		t.Errorf("expected 'Big Bob' after DeletePointer, got '%v'", data)
+	}
+}
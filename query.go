@@ -0,0 +1,667 @@
+package solenodon
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Query returns the Containers matching the given JSONPath expression.
+// The root "$" refers to the Container on which this method is called.
+//
+// The following is supported:
+//   - child names: $.store.book, $['store']['book']
+//   - wildcards: $.friends[*].name, $.store.*
+//   - recursive descent: $..id, $..[0]
+//   - array indices, counting from the end when negative: $.items[0], $.items[-1]
+//   - array slices: $.items[1:3], $.items[::2]
+//   - unions: $.items[0,2], $['a','b']
+//   - filters: $.friends[?(@.id > 1 && @.name != 'Bob')], $.items[?(@.tags)]
+//
+// Each result is linked to its parent, so SetData and Delete on a result modify the original data.
+// Map entries are visited in sorted key order.
+func (c *Container) Query(expr string) ([]*Container, error) {
+	p := &queryParser{input: expr}
+	segments, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, nil
+	}
+	return evalSegments(segments, c, c), nil
+}
+
+type querySegment struct {
+	descendant bool
+	selectors  []querySelector
+}
+
+type querySelector interface {
+	// selectFrom appends the children of node that match the selector.
+	selectFrom(results []*Container, root, node *Container) []*Container
+}
+
+func evalSegments(segments []querySegment, root, node *Container) []*Container {
+	nodes := []*Container{node}
+	for _, segment := range segments {
+		var next []*Container
+		for _, n := range nodes {
+			if segment.descendant {
+				for _, d := range descendantsOrSelf(n) {
+					for _, selector := range segment.selectors {
+						next = selector.selectFrom(next, root, d)
+					}
+				}
+				continue
+			}
+			for _, selector := range segment.selectors {
+				next = selector.selectFrom(next, root, n)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func descendantsOrSelf(c *Container) []*Container {
+	var results []*Container
+	c.Walk(func(d *Container) error {
+		results = append(results, d)
+		return nil
+	})
+	return results
+}
+
+type nameSelector struct {
+	name string
+}
+
+func (s nameSelector) selectFrom(results []*Container, root, node *Container) []*Container {
+	if !isMap(node.data) {
+		return results
+	}
+	if child := node.Get(s.name); child != nil {
+		results = append(results, child)
+	}
+	return results
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectFrom(results []*Container, root, node *Container) []*Container {
+	return append(results, node.Children()...)
+}
+
+type indexSelector struct {
+	index int
+}
+
+func (s indexSelector) selectFrom(results []*Container, root, node *Container) []*Container {
+	length, ok := arrayLength(node.data)
+	if !ok {
+		return results
+	}
+	index := s.index
+	if index < 0 {
+		index += length
+	}
+	if child := node.Get(index); child != nil {
+		results = append(results, child)
+	}
+	return results
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) selectFrom(results []*Container, root, node *Container) []*Container {
+	length, ok := arrayLength(node.data)
+	if !ok || s.step == 0 {
+		return results
+	}
+	normalize := func(i int) int {
+		if i < 0 {
+			return i + length
+		}
+		return i
+	}
+	clamp := func(i, lower, upper int) int {
+		if i < lower {
+			return lower
+		}
+		if i > upper {
+			return upper
+		}
+		return i
+	}
+	if s.step > 0 {
+		start, end := 0, length
+		if s.start != nil {
+			start = clamp(normalize(*s.start), 0, length)
+		}
+		if s.end != nil {
+			end = clamp(normalize(*s.end), 0, length)
+		}
+		for i := start; i < end; i += s.step {
+			results = append(results, node.Get(i))
+		}
+		return results
+	}
+	start, end := length-1, -1
+	if s.start != nil {
+		start = clamp(normalize(*s.start), -1, length-1)
+	}
+	if s.end != nil {
+		end = clamp(normalize(*s.end), -1, length-1)
+	}
+	for i := start; i > end; i += s.step {
+		results = append(results, node.Get(i))
+	}
+	return results
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) selectFrom(results []*Container, root, node *Container) []*Container {
+	for _, child := range node.Children() {
+		if s.expr.test(root, child) {
+			results = append(results, child)
+		}
+	}
+	return results
+}
+
+type filterExpr interface {
+	test(root, current *Container) bool
+}
+
+type valueExpr interface {
+	// value returns the value of the expression, or false if there is none.
+	value(root, current *Container) (interface{}, bool)
+}
+
+type orExpr struct {
+	left, right filterExpr
+}
+
+func (e orExpr) test(root, current *Container) bool {
+	return e.left.test(root, current) || e.right.test(root, current)
+}
+
+type andExpr struct {
+	left, right filterExpr
+}
+
+func (e andExpr) test(root, current *Container) bool {
+	return e.left.test(root, current) && e.right.test(root, current)
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) test(root, current *Container) bool {
+	return !e.expr.test(root, current)
+}
+
+type pathExpr struct {
+	absolute bool
+	segments []querySegment
+}
+
+func (e pathExpr) nodes(root, current *Container) []*Container {
+	if e.absolute {
+		return evalSegments(e.segments, root, root)
+	}
+	return evalSegments(e.segments, root, current)
+}
+
+// test reports whether the path exists.
+func (e pathExpr) test(root, current *Container) bool {
+	return len(e.nodes(root, current)) > 0
+}
+
+func (e pathExpr) value(root, current *Container) (interface{}, bool) {
+	nodes := e.nodes(root, current)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].data, true
+}
+
+type literalExpr struct {
+	data interface{}
+}
+
+func (e literalExpr) value(root, current *Container) (interface{}, bool) {
+	return e.data, true
+}
+
+type compareExpr struct {
+	op          string
+	left, right valueExpr
+}
+
+func (e compareExpr) test(root, current *Container) bool {
+	left, okLeft := e.left.value(root, current)
+	right, okRight := e.right.value(root, current)
+	switch e.op {
+	case "==":
+		return compareEqual(left, okLeft, right, okRight)
+	case "!=":
+		return !compareEqual(left, okLeft, right, okRight)
+	}
+	if !okLeft || !okRight {
+		return false
+	}
+	if x, ok := toFloat64(left); ok {
+		y, ok := toFloat64(right)
+		if !ok {
+			return false
+		}
+		switch {
+		case x < y:
+			return compareOrdered(e.op, -1)
+		case x > y:
+			return compareOrdered(e.op, 1)
+		case x == y:
+			return compareOrdered(e.op, 0)
+		}
+		return false
+	}
+	if x, ok := left.(string); ok {
+		y, ok := right.(string)
+		if !ok {
+			return false
+		}
+		return compareOrdered(e.op, strings.Compare(x, y))
+	}
+	return false
+}
+
+func compareEqual(left interface{}, okLeft bool, right interface{}, okRight bool) bool {
+	if !okLeft || !okRight {
+		return okLeft == okRight
+	}
+	return valuesEqual(left, right)
+}
+
+// compareOrdered applies the ordering operator to the result of comparing two values, which is
+// negative, zero or positive.
+func compareOrdered(op string, cmp int) bool {
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+type queryParser struct {
+	input string
+	pos   int
+}
+
+func (p *queryParser) errorf(msg string) error {
+	return &SyntaxError{Input: p.input, Offset: p.pos, Msg: msg}
+}
+
+func (p *queryParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *queryParser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t' || p.input[p.pos] == '\n' || p.input[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+func (p *queryParser) consume(s string) bool {
+	if strings.HasPrefix(p.input[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseQuery() ([]querySegment, error) {
+	p.skipSpaces()
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with '$'")
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.input) {
+		return nil, p.errorf("unexpected character")
+	}
+	return segments, nil
+}
+
+func (p *queryParser) parseSegments() ([]querySegment, error) {
+	var segments []querySegment
+	for {
+		var segment querySegment
+		switch {
+		case p.consume(".."):
+			segment.descendant = true
+			if p.peek() == '[' {
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+			} else {
+				selector, err := p.parseDotSelector()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = []querySelector{selector}
+			}
+		case p.consume("."):
+			selector, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = []querySelector{selector}
+		case p.peek() == '[':
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = selectors
+		default:
+			return segments, nil
+		}
+		segments = append(segments, segment)
+	}
+}
+
+func (p *queryParser) parseDotSelector() (querySelector, error) {
+	if p.consume("*") {
+		return wildcardSelector{}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.input) && isNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("expected name or '*'")
+	}
+	return nameSelector{name: p.input[start:p.pos]}, nil
+}
+
+func isNameChar(b byte) bool {
+	return b == '_' || b == '-' || b >= 0x80 ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+func (p *queryParser) parseBracket() ([]querySelector, error) {
+	p.pos++ // '['
+	var selectors []querySelector
+	for {
+		p.skipSpaces()
+		selector, err := p.parseBracketSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipSpaces()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *queryParser) parseBracketSelector() (querySelector, error) {
+	switch b := p.peek(); {
+	case b == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case b == '\'' || b == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector{name: name}, nil
+	case b == '?':
+		p.pos++
+		p.skipSpaces()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expr: expr}, nil
+	case b == ':' || b == '-' || (b >= '0' && b <= '9'):
+		return p.parseIndexOrSlice()
+	}
+	return nil, p.errorf("invalid selector")
+}
+
+func (p *queryParser) parseIndexOrSlice() (querySelector, error) {
+	var bounds [3]*int
+	part := 0
+	for {
+		p.skipSpaces()
+		if b := p.peek(); b == '-' || (b >= '0' && b <= '9') {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[part] = &n
+			p.skipSpaces()
+		}
+		if part == 2 || !p.consume(":") {
+			break
+		}
+		part++
+	}
+	if part == 0 {
+		if bounds[0] == nil {
+			return nil, p.errorf("expected index")
+		}
+		return indexSelector{index: *bounds[0]}, nil
+	}
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	return sliceSelector{start: bounds[0], end: bounds[1], step: step}, nil
+}
+
+func (p *queryParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid integer")
+	}
+	return n, nil
+}
+
+func (p *queryParser) parseString() (string, error) {
+	quote := p.input[p.pos]
+	start := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			if p.pos+1 == len(p.input) {
+				p.pos = start
+				return "", p.errorf("unterminated string")
+			}
+			p.pos++
+			switch e := p.input[p.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if p.pos+5 > len(p.input) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.input[p.pos+1:p.pos+5], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				b.WriteByte(e)
+			}
+			p.pos++
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+func (p *queryParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseUnary() (filterExpr, error) {
+	p.skipSpaces()
+	if p.peek() == '!' && !strings.HasPrefix(p.input[p.pos:], "!=") {
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return expr, nil
+	}
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			p.skipSpaces()
+			right, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	if path, ok := left.(pathExpr); ok {
+		return path, nil
+	}
+	return nil, p.errorf("expected comparison operator")
+}
+
+func (p *queryParser) parseValue() (valueExpr, error) {
+	switch b := p.peek(); {
+	case b == '@' || b == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return pathExpr{absolute: b == '$', segments: segments}, nil
+	case b == '\'' || b == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalExpr{data: s}, nil
+	case b == '-' || (b >= '0' && b <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.input) && strings.IndexByte("0123456789.eE+-", p.input[p.pos]) != -1 {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid number")
+		}
+		return literalExpr{data: f}, nil
+	case p.consume("true"):
+		return literalExpr{data: true}, nil
+	case p.consume("false"):
+		return literalExpr{data: false}, nil
+	case p.consume("null"):
+		return literalExpr{data: nil}, nil
+	}
+	return nil, p.errorf("expected value")
+}
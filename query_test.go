@@ -0,0 +1,101 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+var rawQueryJSON = `{
+	"id": "root",
+	"items": [0, 1, 2, 3, 4],
+	"friends": [
+		{"id": 0, "name": "Wood Compton", "tags": ["a"]},
+		{"id": 1, "name": "Nina Andrews"},
+		{"id": 2, "name": "Catalina Newton", "tags": []}
+	],
+	"store": {"a": 1, "b": 2}
+}`
+
+func queryData(t *testing.T, container *Container, expr string) []interface{} {
+	t.Helper()
+	results, err := container.Query(expr)
+	if err != nil {
+		t.Fatalf("unexpected error '%s' for query %q", err, expr)
+	}
+	data := []interface{}{}
+	for _, result := range results {
+		data = append(data, result.Data())
+	}
+	return data
+}
+
+func TestQuery(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawQueryJSON), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr    string
+		dataOut []interface{}
+	}{
+		{expr: "$.id", dataOut: []interface{}{"root"}},
+		{expr: "$['id']", dataOut: []interface{}{"root"}},
+		{expr: "$.missing", dataOut: []interface{}{}},
+		{expr: "$.friends[*].name", dataOut: []interface{}{"Wood Compton", "Nina Andrews", "Catalina Newton"}},
+		{expr: "$.store.*", dataOut: []interface{}{1.0, 2.0}},
+		{expr: "$..id", dataOut: []interface{}{"root", 0.0, 1.0, 2.0}},
+		{expr: "$.items[1:3]", dataOut: []interface{}{1.0, 2.0}},
+		{expr: "$.items[::2]", dataOut: []interface{}{0.0, 2.0, 4.0}},
+		{expr: "$.items[::-2]", dataOut: []interface{}{4.0, 2.0, 0.0}},
+		{expr: "$.items[-2:]", dataOut: []interface{}{3.0, 4.0}},
+		{expr: "$.items[-1]", dataOut: []interface{}{4.0}},
+		{expr: "$.items[0, 4, 9]", dataOut: []interface{}{0.0, 4.0}},
+		{expr: "$['id','store'].a", dataOut: []interface{}{1.0}},
+		{expr: "$.friends[?(@.id > 1)].name", dataOut: []interface{}{"Catalina Newton"}},
+		{expr: "$.friends[?(@.id >= 1 && @.name != 'Catalina Newton')].id", dataOut: []interface{}{1.0}},
+		{expr: "$.friends[?(@.id == 0 || !(@.id < 2))].id", dataOut: []interface{}{0.0, 2.0}},
+		{expr: "$.friends[?(@.tags)].id", dataOut: []interface{}{0.0, 2.0}},
+		{expr: "$.friends[?(@.name == $.friends[1].name)].id", dataOut: []interface{}{1.0}},
+		{expr: `$.items[?@ > 2]`, dataOut: []interface{}{3.0, 4.0}},
+	}
+	for i, test := range tests {
+		data := queryData(t, container, test.expr)
+		if !reflect.DeepEqual(data, test.dataOut) {
+			t.Errorf("%d, %s: expected %v, got %v", i, test.expr, test.dataOut, data)
+		}
+	}
+}
+
+func TestQueryInvalid(t *testing.T) {
+	exprs := []string{"", "foo", "$.", "$[", "$['a'", "$[?(@.a ==)]", "$[?(@.a > 1]", "$[1:2:3:4]", "$ x", "$[?(1)]"}
+	container := NewContainer(map[string]interface{}{})
+	for i, expr := range exprs {
+		if _, err := container.Query(expr); err == nil {
+			t.Errorf("%d, expected error for query %q", i, expr)
+		}
+	}
+}
+
+func TestQueryResultsModifyOriginal(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(rawTOML), toml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := container.Query("$.friends[?(@.id >= 1)].name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.SetData("Big Bob") == nil {
+			t.Fatal("unexpected nil container after SetData")
+		}
+	}
+	names := queryData(t, container, "$.friends[*].name")
+	expected := []interface{}{"Wood Compton", "Big Bob", "Big Bob"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,323 @@
+package solenodon
+
+import (
+	"encoding"
+	"reflect"
+	"sort"
+)
+
+// reflectDecoder converts data into the types of Go values that are traversed with reflection.
+// Struct fields are matched by their json tag, as for values that are marshaled with encoding/json.
+var reflectDecoder = &decoder{tagName: "json"}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// indirect follows pointers and interfaces. The result is invalid if a nil pointer is found.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// pointerOf returns the identity of data if it is a Go pointer or map, through which data can contain itself.
+func pointerOf(data interface{}) (pointerKey, bool) {
+	v := reflect.ValueOf(data)
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Map) && !v.IsNil() {
+		return pointerKey{t: v.Type(), p: v.Pointer()}, true
+	}
+	return pointerKey{}, false
+}
+
+// pointerPairs holds the pairs of pointers and maps that are being compared, so that cyclic values end.
+type pointerPairs map[[2]pointerKey]bool
+
+// enter marks a and b as being compared, and returns the function that unmarks them.
+// The result is false if they already are being compared.
+func (p *pointerPairs) enter(a, b interface{}) (func(), bool) {
+	keyA, okA := pointerOf(a)
+	keyB, okB := pointerOf(b)
+	if !okA || !okB {
+		return func() {}, true
+	}
+	pair := [2]pointerKey{keyA, keyB}
+	if (*p)[pair] {
+		return nil, false
+	}
+	if *p == nil {
+		*p = pointerPairs{}
+	}
+	(*p)[pair] = true
+	return func() { delete(*p, pair) }, true
+}
+
+// reflectAdapter is the NodeAdapter for data of any map, slice, array, struct or pointer type without a registered
+// adapter. Struct fields are matched by the name in their json tag or by their field name.
+type reflectAdapter struct{}
+
+func (a reflectAdapter) Child(data, key interface{}) (interface{}, bool) {
+	value, reason := a.lookupChild(data, key)
+	return value, reason == 0
+}
+
+func (reflectAdapter) lookupChild(data, key interface{}) (interface{}, Reason) {
+	v := indirect(reflect.ValueOf(data))
+	switch v.Kind() {
+	case reflect.Map:
+		k, ok := reflectKey(key, v.Type().Key())
+		if !ok {
+			return nil, ReasonInvalidKey
+		}
+		value := v.MapIndex(k)
+		if !value.IsValid() {
+			return nil, ReasonKeyNotFound
+		}
+		return value.Interface(), 0
+	case reflect.Slice, reflect.Array:
+		i, ok := key.(int)
+		if !ok {
+			return nil, ReasonInvalidKey
+		}
+		if i < 0 || i >= v.Len() {
+			return nil, ReasonIndexOutOfRange
+		}
+		return v.Index(i).Interface(), 0
+	case reflect.Struct:
+		index, ok := structFieldIndex(v.Type(), key)
+		if !ok {
+			return nil, ReasonKeyNotFound
+		}
+		for i, x := range index {
+			if i > 0 {
+				if v = indirect(v); !v.IsValid() {
+					// The field is promoted from a nil embedded struct pointer.
+					return nil, ReasonKeyNotFound
+				}
+			}
+			v = v.Field(x)
+		}
+		if !v.CanInterface() {
+			return nil, ReasonKeyNotFound
+		}
+		return v.Interface(), 0
+	}
+	return nil, ReasonNotTraversable
+}
+
+// Shape reports maps and structs as maps, and slices and arrays as sequences.
+// A []byte and a struct that marshals itself as text, such as time.Time, have no children.
+func (reflectAdapter) Shape(data interface{}) Shape {
+	v := indirect(reflect.ValueOf(data))
+	switch v.Kind() {
+	case reflect.Map:
+		return ShapeMap
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return ShapeScalar
+		}
+		return ShapeSequence
+	case reflect.Struct:
+		if v.Type().Implements(textMarshalerType) || reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+			return ShapeScalar
+		}
+		return ShapeMap
+	}
+	return ShapeScalar
+}
+
+// SetChild converts the value to the type of the elements as by Decode.
+// Arrays and structs that are not behind a pointer cannot be modified in place, so a modified copy is returned.
+func (reflectAdapter) SetChild(data, key, value interface{}) (interface{}, bool) {
+	v := indirect(reflect.ValueOf(data))
+	if !v.IsValid() {
+		return nil, false
+	}
+	copied := false
+	if (v.Kind() == reflect.Array || v.Kind() == reflect.Struct) && !v.CanAddr() {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+		copied = true
+	}
+	var target reflect.Value
+	switch v.Kind() {
+	case reflect.Map:
+		k, ok := reflectKey(key, v.Type().Key())
+		if !ok || !v.MapIndex(k).IsValid() {
+			return nil, false
+		}
+		element, ok := reflectValue(value, v.Type().Elem())
+		if !ok {
+			return nil, false
+		}
+		v.SetMapIndex(k, element)
+		return data, true
+	case reflect.Slice, reflect.Array:
+		i, ok := key.(int)
+		if !ok || i < 0 || i >= v.Len() {
+			return nil, false
+		}
+		target = v.Index(i)
+	case reflect.Struct:
+		index, ok := structFieldIndex(v.Type(), key)
+		if !ok {
+			return nil, false
+		}
+		field, err := fieldByIndex(v, index)
+		if err != nil {
+			return nil, false
+		}
+		target = field
+	default:
+		return nil, false
+	}
+	element, ok := reflectValue(value, target.Type())
+	if !ok || !target.CanSet() {
+		return nil, false
+	}
+	target.Set(element)
+	if copied {
+		return v.Interface(), true
+	}
+	return data, true
+}
+
+// AddChild only adds values to maps. A nil value is added as an empty map if the map holds maps,
+// so that Set can descend into it.
+func (reflectAdapter) AddChild(data, key, value interface{}) (interface{}, bool) {
+	v := indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Map || v.IsNil() {
+		return nil, false
+	}
+	k, ok := reflectKey(key, v.Type().Key())
+	if !ok {
+		return nil, false
+	}
+	if value == nil && v.Type().Elem().Kind() == reflect.Map {
+		v.SetMapIndex(k, reflect.MakeMap(v.Type().Elem()))
+		return data, true
+	}
+	element, ok := reflectValue(value, v.Type().Elem())
+	if !ok {
+		return nil, false
+	}
+	v.SetMapIndex(k, element)
+	return data, true
+}
+
+// DeleteChild shortens slices that are behind a pointer in place, and returns a shortened copy of other slices.
+// Elements of arrays and fields of structs cannot be deleted.
+func (reflectAdapter) DeleteChild(data, key interface{}) (interface{}, bool) {
+	v := indirect(reflect.ValueOf(data))
+	switch v.Kind() {
+	case reflect.Map:
+		k, ok := reflectKey(key, v.Type().Key())
+		if !ok || !v.MapIndex(k).IsValid() {
+			return nil, false
+		}
+		v.SetMapIndex(k, reflect.Value{})
+		return data, true
+	case reflect.Slice:
+		i, ok := key.(int)
+		if !ok || i < 0 || i >= v.Len() {
+			return nil, false
+		}
+		slice := reflect.MakeSlice(v.Type(), 0, v.Len()-1)
+		slice = reflect.AppendSlice(reflect.AppendSlice(slice, v.Slice(0, i)), v.Slice(i+1, v.Len()))
+		if v.CanSet() {
+			v.Set(slice)
+			return data, true
+		}
+		return slice.Interface(), true
+	}
+	return nil, false
+}
+
+// Keys sorts map keys, see lessKey, and returns struct fields in the order in which they are declared.
+// Data that Shape reports as a scalar has no keys.
+func (a reflectAdapter) Keys(data interface{}) []interface{} {
+	if a.Shape(data) == ShapeScalar {
+		return nil
+	}
+	v := indirect(reflect.ValueOf(data))
+	switch v.Kind() {
+	case reflect.Map:
+		keys := make([]interface{}, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.Interface())
+		}
+		sort.SliceStable(keys, func(i, j int) bool { return lessKey(keys[i], keys[j]) })
+		return keys
+	case reflect.Slice, reflect.Array:
+		return indexKeys(v.Len())
+	case reflect.Struct:
+		fields := reflectDecoder.structFields(v.Type(), nil)
+		keys := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			if _, ok := a.Child(v.Interface(), field.name); ok {
+				keys = append(keys, field.name)
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+// structFieldIndex returns the index of the exported field of the struct type that matches the key,
+// by the name in its json tag or else by its field name.
+func structFieldIndex(t reflect.Type, key interface{}) ([]int, bool) {
+	name, ok := key.(string)
+	if !ok {
+		return nil, false
+	}
+	fields := reflectDecoder.structFields(t, nil)
+	for _, field := range fields {
+		if field.name == name {
+			return field.index, true
+		}
+	}
+	for _, field := range fields {
+		if t.FieldByIndex(field.index).Name == name {
+			return field.index, true
+		}
+	}
+	return nil, false
+}
+
+// reflectKey converts the key into a map key of the given type. Keys that cannot be hashed are rejected.
+func reflectKey(key interface{}, t reflect.Type) (reflect.Value, bool) {
+	if key == nil {
+		if t.Kind() == reflect.Interface {
+			return reflect.Zero(t), true
+		}
+		return reflect.Value{}, false
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		return reflect.Value{}, false
+	}
+	if k := reflect.ValueOf(key); k.Type().AssignableTo(t) {
+		return k, true
+	}
+	k := reflect.New(t).Elem()
+	if err := reflectDecoder.decodeKey(key, k); err != nil {
+		return reflect.Value{}, false
+	}
+	return k, true
+}
+
+// reflectValue converts the data into a value of the given type, decoding it if it is not assignable.
+func reflectValue(data interface{}, t reflect.Type) (reflect.Value, bool) {
+	if data != nil {
+		if v := reflect.ValueOf(data); v.Type().AssignableTo(t) {
+			return v, true
+		}
+	}
+	v := reflect.New(t).Elem()
+	if err := reflectDecoder.decode(data, v, nil); err != nil {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
@@ -0,0 +1,200 @@
+package solenodon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type reflectServer struct {
+	Host   string `json:"host"`
+	Port   int
+	Tags   []string          `json:"tags"`
+	Meta   map[string]string `json:"meta"`
+	hidden string
+}
+
+func TestReflectGet(t *testing.T) {
+	server := &reflectServer{Host: "alpha", Port: 8080, Tags: []string{"a", "b"}, Meta: map[string]string{"zone": "eu"}, hidden: "x"}
+	container := NewContainer(map[string]interface{}{
+		"server":  server,
+		"ports":   [2]uint16{80, 443},
+		"weights": map[int]float64{1: 0.5},
+		"nil":     (*reflectServer)(nil),
+		"any":     map[interface{}]int{1: 2},
+	})
+	tests := []struct {
+		keys     []interface{}
+		expected interface{}
+		reason   Reason
+	}{
+		{[]interface{}{"server", "host"}, "alpha", 0},
+		{[]interface{}{"server", "Host"}, "alpha", 0},
+		{[]interface{}{"server", "Port"}, 8080, 0},
+		{[]interface{}{"server", "tags", 1}, "b", 0},
+		{[]interface{}{"server", "meta", "zone"}, "eu", 0},
+		{[]interface{}{"ports", 1}, uint16(443), 0},
+		{[]interface{}{"weights", 1}, 0.5, 0},
+		{[]interface{}{"server", "hidden"}, nil, ReasonKeyNotFound},
+		{[]interface{}{"server", "tags", 2}, nil, ReasonIndexOutOfRange},
+		{[]interface{}{"server", "tags", "x"}, nil, ReasonInvalidKey},
+		{[]interface{}{"server", "meta", "missing"}, nil, ReasonKeyNotFound},
+		{[]interface{}{"nil", "host"}, nil, ReasonNotTraversable},
+		{[]interface{}{"any", 1}, 2, 0},
+		{[]interface{}{"any", []int{1}}, nil, ReasonInvalidKey},
+	}
+	for i, test := range tests {
+		result, err := container.Lookup(test.keys...)
+		if test.reason != 0 {
+			if pathErr, ok := err.(*PathError); !ok || pathErr.Reason != test.reason {
+				t.Errorf("%d, expected reason %s, got %v", i, test.reason, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d, unexpected error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(result.Data(), test.expected) {
+			t.Errorf("%d, expected %v (%T), got %v (%T)", i, test.expected, test.expected, result.Data(), result.Data())
+		}
+	}
+	if keys := container.Get("server").Keys(); !reflect.DeepEqual(keys, []interface{}{"host", "Port", "tags", "meta"}) {
+		t.Errorf("unexpected struct keys %v", keys)
+	}
+	if n := container.Get("ports").Len(); n != 2 {
+		t.Errorf("expected 2 ports, got %d", n)
+	}
+}
+
+func TestReflectSetData(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte(`{"tags":["x"],"balance":"1"}`), json.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.Get("tags").SetData([]string{"a", "b", "c"})
+	if result := container.Get("tags", 1).SetData("B"); result == nil || result.Data() != "B" {
+		t.Errorf("expected to set string slice element, got %v", result)
+	}
+	container.Get("balance").SetData(struct {
+		Currency string `json:"currency"`
+		Cents    int    `json:"cents"`
+	}{Currency: "euro", Cents: 100})
+	if result := container.Get("balance", "cents").SetData(29077.0); result == nil || result.Data() != 29077 {
+		t.Errorf("expected to set converted struct field, got %v", result)
+	}
+	container.Get("balance").SetData(map[string]int{"cents": 1})
+	if result := container.Get("balance", "cents").SetData("x"); result != nil {
+		t.Errorf("expected failure for inconvertible data, got %v", result.Data())
+	}
+	container.Set(&reflectServer{}, "server")
+	container.Get("server", "Port").SetData(9090)
+	container.Set([2]string{}, "pair")
+	container.Get("pair", 0).SetData("first")
+	b, err := json.Marshal(container.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"balance":{"cents":1},"pair":["first",""],"server":{"host":"","Port":9090,"tags":null,"meta":null},"tags":["a","B","c"]}`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+}
+
+func TestReflectDelete(t *testing.T) {
+	server := &reflectServer{Tags: []string{"a", "b", "c"}, Meta: map[string]string{"zone": "eu", "rack": "1"}}
+	container := NewContainer(map[string]interface{}{
+		"server": server,
+		"ids":    []int{1, 2, 3},
+		"pair":   [2]int{1, 2},
+	})
+	container.Delete("server", "tags", 1).Delete("server", "meta", "rack").Delete("ids", 0).Delete("pair", 0).Delete("server", "Port")
+	if !reflect.DeepEqual(server.Tags, []string{"a", "c"}) {
+		t.Errorf("unexpected tags %v", server.Tags)
+	}
+	if !reflect.DeepEqual(server.Meta, map[string]string{"zone": "eu"}) {
+		t.Errorf("unexpected meta %v", server.Meta)
+	}
+	if data := container.Get("ids").Data(); !reflect.DeepEqual(data, []int{2, 3}) {
+		t.Errorf("unexpected ids %v", data)
+	}
+	if data := container.Get("pair").Data(); !reflect.DeepEqual(data, [2]int{1, 2}) {
+		t.Errorf("unexpected pair %v", data)
+	}
+}
+
+func TestReflectSet(t *testing.T) {
+	container := NewContainer(map[string]map[string]string{})
+	if container.Set("eu", "meta", "zone") == nil {
+		t.Fatal("expected Set to create a typed map entry")
+	}
+	if data := container.Data(); !reflect.DeepEqual(data, map[string]map[string]string{"meta": {"zone": "eu"}}) {
+		t.Errorf("unexpected data %v", data)
+	}
+	any := NewContainer(map[interface{}]int{1: 2})
+	if any.Set(3, []int{1}) != nil || len(any.Delete([]int{1}).Data().(map[interface{}]int)) != 1 {
+		t.Errorf("expected unhashable key to be rejected, got %v", any.Data())
+	}
+}
+
+func TestReflectBytesHaveNoChildren(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"b": []byte("hi"), "a": [2]byte{1, 2}})
+	if flat := container.Flatten("."); !reflect.DeepEqual(flat, map[string]interface{}{"b": []byte("hi"), "a": [2]byte{1, 2}}) {
+		t.Errorf("unexpected flattened data %v", flat)
+	}
+	if n := container.Get("b").Len(); n != 0 {
+		t.Errorf("expected no children, got %d", n)
+	}
+	visited := 0
+	container.Walk(func(*Container) error {
+		visited++
+		return nil
+	})
+	if visited != 3 {
+		t.Errorf("expected 3 visited Containers, got %d", visited)
+	}
+}
+
+type reflectNode struct {
+	Name string
+	Next *reflectNode
+}
+
+func TestReflectCycles(t *testing.T) {
+	newCycle := func() *reflectNode {
+		n := &reflectNode{Name: "n"}
+		n.Next = n
+		return n
+	}
+	container := NewContainer(map[string]interface{}{"n": newCycle()})
+	var paths []string
+	if err := container.Walk(func(c *Container) error {
+		paths = append(paths, FormatPath(c.Path()...))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(paths, []string{"", "n", "n.Name"}) {
+		t.Errorf("unexpected walked paths %v", paths)
+	}
+	if flat := container.Flatten("."); !reflect.DeepEqual(flat, map[string]interface{}{"n.Name": "n"}) {
+		t.Errorf("unexpected flattened data %v", flat)
+	}
+	if results, err := container.Query("$..Name"); err != nil || len(results) != 1 {
+		t.Errorf("expected 1 result, got %d (%v)", len(results), err)
+	}
+	other := NewContainer(map[string]interface{}{"n": newCycle()})
+	if !container.Equal(other) {
+		t.Error("expected equal cycles")
+	}
+	if changes := Diff(container, other); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+	other.Get("n", "Name").SetData("m")
+	if container.Equal(other) {
+		t.Error("expected different cycles")
+	}
+	if changes := Diff(container, other); len(changes) != 1 {
+		t.Errorf("expected 1 change, got %v", changes)
+	}
+}
@@ -1,10 +1,16 @@
 // Package solenodon provides resources for dealing with deserialized data for which the structure is dynamic or unknown.
 package solenodon
 
+import "fmt"
+
 // Note that encoding/json by default will parse:
 // - all number values into float64
 // Note that github.com/BurntSushi/toml by default will parse:
 // - all integer values into int64
+// Note that gopkg.in/yaml.v3 by default will parse:
+// - integer values into int, or into int64 or uint64 if they do not fit an int
+// - maps into map[string]interface{} or, if a key is not a string, into map[interface{}]interface{}
+// The typed accessors, such as Int and Float64, convert between all of these numeric types.
 // Note that encoding/xml cannot be mapped to an interface{}
 
 // Container contains data
@@ -41,49 +47,88 @@ func (c *Container) Data() interface{} {
 	return c.data
 }
 
+// Parent returns the Container from which this Container was derived with Get, or nil if there is none.
+func (c *Container) Parent() *Container {
+	if c == nil {
+		return nil
+	}
+	return c.parent
+}
+
+// Root returns the Container at the top of the chain of parents.
+func (c *Container) Root() *Container {
+	if c == nil {
+		return nil
+	}
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+// Key returns the key at which this Container is found in its parent, or nil if there is no parent.
+func (c *Container) Key() interface{} {
+	if c == nil {
+		return nil
+	}
+	return c.key
+}
+
+// Depth returns the number of parents of the Container.
+func (c *Container) Depth() int {
+	depth := 0
+	for n := c.Parent(); n != nil; n = n.parent {
+		depth++
+	}
+	return depth
+}
+
+// Path returns the keys that lead from the root to this Container,
+// such that c.Root().Get(c.Path()...) refers to the same value.
+func (c *Container) Path() []interface{} {
+	path := make([]interface{}, c.Depth())
+	for n, i := c, len(path)-1; i >= 0; n, i = n.parent, i-1 {
+		path[i] = n.key
+	}
+	return path
+}
+
+// PathString returns the path of the Container in the syntax of ParsePath.
+func (c *Container) PathString() string {
+	return FormatPath(c.Path()...)
+}
+
+// Pointer returns the path of the Container as a JSON Pointer.
+func (c *Container) Pointer() string {
+	return FormatPointer(c.Path()...)
+}
+
 // Get returns a Container containing the value following the path of the given keys.
+// Go maps, slices, arrays, pointers and structs are traversed, matching exported fields by json tag or name.
+// Other types can be supported with RegisterAdapter.
 // The returned container will be nil if no result was found.
+// Use Lookup to find out why no result was found.
 func (c *Container) Get(keys ...interface{}) *Container {
 	if c == nil {
 		return c
 	}
+	result, _, _ := c.lookup(keys)
+	return result
+}
+
+// lookup follows the path of the given keys.
+// If no result was found, the index of the failing key and the reason are returned.
+func (c *Container) lookup(keys []interface{}) (*Container, int, Reason) {
 	result := c
-	for _, key := range keys {
-		var data interface{}
-		switch w := result.data.(type) {
-		case map[string]interface{}:
-			if v, ok := key.(string); ok {
-				data, ok = w[v]
-				if !ok {
-					return nil
-				}
-			} else {
-				return nil
-			}
-		case map[interface{}]interface{}:
-			var ok bool
-			data, ok = w[key]
-			if !ok {
-				return nil
-			}
-		case []interface{}:
-			if v, ok := key.(int); ok {
-				if v < 0 || v >= len(w) {
-					return nil
-				}
-				data = w[v]
-			} else {
-				return nil
-			}
-		case []map[string]interface{}:
-			if v, ok := key.(int); ok {
-				if v < 0 || v >= len(w) {
-					return nil
-				}
-				data = w[v]
-			}
-		default:
-			return nil
+	for i, key := range keys {
+		key, ok := resolveIndex(result.data, key)
+		if !ok {
+			return nil, i, ReasonInvalidKey
+		}
+		data, reason := child(result.data, key)
+		if reason != 0 {
+			return nil, i, reason
 		}
 		result = &Container{
 			data:   data,
@@ -91,7 +136,20 @@ func (c *Container) Get(keys ...interface{}) *Container {
 			key:    key,
 		}
 	}
-	return result
+	return result, 0, 0
+}
+
+// child returns the value at the given key in the given data, using its NodeAdapter.
+// The returned Reason is zero if the value was found.
+func child(data, key interface{}) (interface{}, Reason) {
+	adapter := adapterOf(data)
+	if l, ok := adapter.(childLookup); ok {
+		return l.lookupChild(data, key)
+	}
+	if value, ok := adapter.Child(data, key); ok {
+		return value, 0
+	}
+	return nil, ReasonKeyNotFound
 }
 
 // Has returns true if the Container has a value for the given keys.
@@ -100,6 +158,7 @@ func (c *Container) Has(keys ...interface{}) bool {
 }
 
 // Delete the value, if any, at the end of the path of the given keys.
+// Elements of arrays and fields of structs cannot be deleted.
 // The Container on which this method is called will be returned.
 func (c *Container) Delete(keys ...interface{}) *Container {
 	if c == nil {
@@ -113,28 +172,18 @@ func (c *Container) Delete(keys ...interface{}) *Container {
 	if parent == nil {
 		return c
 	}
-	lastKey := keys[len(keys)-1]
-
-	switch w := parent.data.(type) {
-	case map[string]interface{}:
-		if v, ok := lastKey.(string); ok {
-			delete(w, v)
-		}
-	case map[interface{}]interface{}:
-		delete(w, lastKey)
-	case []interface{}:
-		if v, ok := lastKey.(int); ok && v >= 0 && v < len(w) {
-			parent.SetData(append(w[:v], w[v+1:]...))
-		}
-	case []map[string]interface{}:
-		if v, ok := lastKey.(int); ok && v >= 0 && v < len(w) {
-			parent.SetData(append(w[:v], w[v+1:]...))
-		}
+	key, ok := resolveIndex(parent.data, keys[len(keys)-1])
+	if !ok {
+		return c
+	}
+	if data, ok := adapterOf(parent.data).DeleteChild(parent.data, key); ok && !sameNode(data, parent.data) {
+		parent.SetData(data)
 	}
 	return c
 }
 
-// SetData sets the given data in the Container.
+// SetData sets the given data in the Container, using the NodeAdapter of its parent.
+// Data set in a Go value is converted to the type of its elements as by Decode.
 // The Container on which this method is called will be returned.
 func (c *Container) SetData(data interface{}) *Container {
 	// TODO panic if parent does not contains key?
@@ -145,43 +194,221 @@ func (c *Container) SetData(data interface{}) *Container {
 		c.data = data
 		return c
 	}
-	switch w := c.parent.data.(type) {
-	case map[string]interface{}:
-		if v, ok := c.key.(string); ok {
-			if _, ok := w[v]; ok {
-				w[v] = data
-			} else {
-				return nil
+	parentData, ok := adapterOf(c.parent.data).SetChild(c.parent.data, c.key, data)
+	if !ok || (!sameNode(parentData, c.parent.data) && c.parent.SetData(parentData) == nil) {
+		return nil
+	}
+	// The adapter may have converted the data, e.g. to the element type of a Go slice.
+	if stored, reason := child(c.parent.data, c.key); reason == 0 {
+		data = stored
+	}
+	c.data = data
+	return c
+}
+
+// Set sets the given data at the end of the path of the given keys, creating missing maps and slices along the way.
+// An int or Index key creates a slice, which is grown with nil values by at most MaxSliceGrowth.
+// A new map is a map[interface{}]interface{} if the nearest map above it is one or the key is not a string.
+// The Container at the end of the path will be returned, or nil if the data could not be set.
+// Nothing is created if the data could not be set.
+func (c *Container) Set(data interface{}, keys ...interface{}) *Container {
+	result, _, _ := c.set(data, keys)
+	return result
+}
+
+// set sets the data like Set. If it could not be set, the index of the failing key and the reason are returned.
+// The reason is 0 if the data itself could not be set.
+func (c *Container) set(data interface{}, keys []interface{}) (*Container, int, Reason) {
+	if c == nil {
+		return nil, 0, ReasonNilContainer
+	}
+	current := c
+	for i, key := range keys {
+		// The missing part of the path is built apart from the data, and only attached once all of it succeeded.
+		if current.data == nil {
+			node, j, reason := newPath(keys[i:], data, current.mapFlavor())
+			if reason != 0 {
+				return nil, i + j, reason
 			}
-		} else {
-			return nil
-		}
-	case map[interface{}]interface{}:
-		if _, ok := w[c.key]; ok {
-			w[c.key] = data
-		} else {
-			return nil
+			if current.SetData(node) == nil {
+				return nil, len(keys) - 1, 0
+			}
+			return current.Get(keys[i:]...), 0, 0
 		}
-	case []interface{}:
-		if v, ok := c.key.(int); ok && v >= 0 && v < len(w) {
-			w[v] = data
-		} else {
-			return nil
+		key, ok := resolveIndex(current.data, key)
+		if !ok {
+			return nil, i, ReasonInvalidKey
 		}
-	case []map[string]interface{}:
-		if v, ok := c.key.(int); ok && v >= 0 && v < len(w) {
-			parentData := make([]interface{}, len(w))
-			for i, x := range w {
-				parentData[i] = x
+		next := current.Get(key)
+		if next == nil {
+			node, j, reason := newPath(keys[i+1:], data, current.mapFlavor())
+			if reason != 0 {
+				return nil, i + 1 + j, reason
+			}
+			if !current.addChild(key, node) {
+				_, reason := child(current.data, key)
+				return nil, i, reason
 			}
-			parentData[v] = data
-			c.parent.SetData(parentData)
-		} else {
-			return nil
+			return current.Get(append([]interface{}{key}, keys[i+1:]...)...), 0, 0
 		}
-	default:
-		return nil
+		current = next
 	}
-	c.data = data
-	return c
+	if result := current.SetData(data); result != nil {
+		return result, 0, 0
+	}
+	return nil, len(keys) - 1, 0
+}
+
+// newPath returns the data nested in new slices and maps at the path of the given keys.
+// New maps have the flavor of the nearest map above them, see newNode.
+// If a key cannot be added, its index and the reason are returned.
+func newPath(keys []interface{}, data, flavor interface{}) (interface{}, int, Reason) {
+	if len(keys) == 0 {
+		return data, 0, 0
+	}
+	node := newNode(keys[0], flavor)
+	if isMap(node) {
+		flavor = node
+	}
+	child, i, reason := newPath(keys[1:], data, flavor)
+	if reason != 0 {
+		return nil, i + 1, reason
+	}
+	key, _ := resolveIndex(node, keys[0])
+	node, ok := adapterOf(node).(ChildAdder).AddChild(node, key, child)
+	if !ok {
+		return nil, 0, ReasonIndexOutOfRange
+	}
+	return node, 0, 0
+}
+
+// newNode returns an empty slice or map that can hold the given key, with the flavor of the nearest map.
+func (c *Container) newNode(key interface{}) interface{} {
+	return newNode(key, c.mapFlavor())
+}
+
+// mapFlavor returns the data of the nearest Container at or above this one that holds a map[string]interface{}
+// or a map[interface{}]interface{}, or nil if there is none.
+func (c *Container) mapFlavor() interface{} {
+	for n := c; n != nil; n = n.parent {
+		switch n.data.(type) {
+		case map[interface{}]interface{}, map[string]interface{}:
+			return n.data
+		}
+	}
+	return nil
+}
+
+// newNode returns an empty slice or map that can hold the given key.
+// A new map is a map[interface{}]interface{} if the flavor is one or the key is not a string.
+func newNode(key, flavor interface{}) interface{} {
+	switch key.(type) {
+	case int, Index:
+		return []interface{}{}
+	case string:
+		if _, ok := flavor.(map[interface{}]interface{}); ok {
+			return map[interface{}]interface{}{}
+		}
+		return map[string]interface{}{}
+	}
+	return map[interface{}]interface{}{}
+}
+
+// addChild adds the given child at the given key, which must not yet be present.
+// Slices are grown to make room for the key.
+func (c *Container) addChild(key, child interface{}) bool {
+	adder, ok := adapterOf(c.data).(ChildAdder)
+	if !ok {
+		return false
+	}
+	data, ok := adder.AddChild(c.data, key, child)
+	if !ok {
+		return false
+	}
+	return sameNode(data, c.data) || c.SetData(data) != nil
+}
+
+// childKeys returns the keys of the children of the given data, in a deterministic order, using its NodeAdapter.
+// Map keys are sorted, see lessKey. The result is nil if the data has no children.
+func childKeys(data interface{}) []interface{} {
+	return adapterOf(data).Keys(data)
+}
+
+func indexKeys(n int) []interface{} {
+	keys := make([]interface{}, n)
+	for i := range keys {
+		keys[i] = i
+	}
+	return keys
+}
+
+// lessKey orders map keys of mixed types, such as the keys of a map[interface{}]interface{} decoded by yaml.v3.
+// Keys are first ordered by kind: nil, booleans, numbers, strings and then anything else.
+// Keys of the same kind are ordered by value, and numbers of equal value by the name of their type.
+func lessKey(a, b interface{}) bool {
+	rankA, rankB := keyRank(a), keyRank(b)
+	if rankA != rankB {
+		return rankA < rankB
+	}
+	switch rankA {
+	case 1:
+		return !a.(bool) && b.(bool)
+	case 2:
+		x, _ := toFloat64(a)
+		y, _ := toFloat64(b)
+		if x != y {
+			return x < y
+		}
+		return fmt.Sprintf("%T %v", a, a) < fmt.Sprintf("%T %v", b, b)
+	case 3:
+		return a.(string) < b.(string)
+	case 4:
+		return fmt.Sprintf("%T %v", a, a) < fmt.Sprintf("%T %v", b, b)
+	}
+	return false
+}
+
+func keyRank(key interface{}) int {
+	switch key.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
+
+// toFloat64 converts any of the numeric types produced by the supported decoders to a float64.
+func toFloat64(data interface{}) (float64, bool) {
+	switch v := data.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
 }
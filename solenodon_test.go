@@ -1,6 +1,7 @@
 package solenodon
 
 import (
+	"reflect"
 	"testing"
 	"time"
 )
@@ -235,3 +236,140 @@ func TestContainerHasNot(t *testing.T) {
 		t.Error("did not expect container to have key 'foo.bar'")
 	}
 }
+
+func TestSetCreatesMissingNodes(t *testing.T) {
+	container := NewContainer(nil)
+	if container.Set("10.0.0.1", "database", "replica", "host") == nil {
+		t.Fatal("unexpected nil container after Set")
+	}
+	if container.Set(8080, "database", "ports", 1) == nil {
+		t.Fatal("unexpected nil container after Set")
+	}
+	expected := map[string]interface{}{
+		"database": map[string]interface{}{
+			"replica": map[string]interface{}{"host": "10.0.0.1"},
+			"ports":   []interface{}{nil, 8080},
+		},
+	}
+	if !reflect.DeepEqual(container.Data(), expected) {
+		t.Errorf("expected %v, got %v", expected, container.Data())
+	}
+}
+
+func TestSetOverwritesExistingValue(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"foo": []interface{}{1, 2}})
+	if out := container.Set(3, "foo", 1); out == nil || out.Data() != 3 {
+		t.Fatalf("expected container with data 3, got %v", out)
+	}
+	if !reflect.DeepEqual(container.Get("foo").Data(), []interface{}{1, 3}) {
+		t.Errorf("unexpected data %v", container.Get("foo").Data())
+	}
+}
+
+func TestSetMatchesMapFlavor(t *testing.T) {
+	container := NewContainer(map[interface{}]interface{}{"foo": nil})
+	container.Set(true, "foo", "bar")
+	if _, ok := container.Get("foo").Data().(map[interface{}]interface{}); !ok {
+		t.Errorf("expected map[interface{}]interface{}, got %T", container.Get("foo").Data())
+	}
+}
+
+func TestSetGrowsStringMapSlice(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"friends": []map[string]interface{}{{"id": 0}}})
+	container.Set("Bob", "friends", 1, "name")
+	expected := []interface{}{
+		map[string]interface{}{"id": 0},
+		map[string]interface{}{"name": "Bob"},
+	}
+	if !reflect.DeepEqual(container.Get("friends").Data(), expected) {
+		t.Errorf("expected %v, got %v", expected, container.Get("friends").Data())
+	}
+}
+
+func TestSetFailsOnValueInTheWay(t *testing.T) {
+	container := NewContainer(map[string]interface{}{"foo": "bar", "list": []interface{}{}})
+	if container.Set(1, "foo", "baz") != nil {
+		t.Error("expected nil when setting through a string")
+	}
+	if container.Set(1, "list", "baz") != nil {
+		t.Error("expected nil when setting a string key in a slice")
+	}
+	if container.Set(1, "list", -1) != nil {
+		t.Error("expected nil when setting a negative index")
+	}
+	if container.Set(1, "list", MaxSliceGrowth+1) != nil {
+		t.Error("expected nil when growing a slice by too many elements")
+	}
+	if container.Set(1, "list", MaxSliceGrowth) == nil || container.Get("list").Len() != MaxSliceGrowth+1 {
+		t.Error("expected slice to grow by MaxSliceGrowth elements")
+	}
+}
+
+func TestSetFailureLeavesDataUnchanged(t *testing.T) {
+	tests := []struct {
+		data interface{}
+		keys []interface{}
+	}{
+		{map[string]interface{}{}, []interface{}{"x", "y", Index(-1)}},
+		{map[string]interface{}{}, []interface{}{"x", 0, MaxSliceGrowth + 1}},
+		{map[string]interface{}{"x": nil}, []interface{}{"x", "y", -1}},
+		{nil, []interface{}{"x", "y", Index(-1)}},
+	}
+	for i, test := range tests {
+		container := NewContainer(test.data)
+		if container.Set(1, test.keys...) != nil {
+			t.Errorf("%d, expected nil", i)
+		}
+		if !reflect.DeepEqual(container.Data(), test.data) {
+			t.Errorf("%d, expected data to be unchanged, got %v", i, container.Data())
+		}
+	}
+}
+
+func TestSetOnNilContainer(t *testing.T) {
+	var container *Container
+	if container.Set(1, "foo") != nil {
+		t.Error("expected Set on nil container to return nil")
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	root := NewContainer(map[string]interface{}{
+		"items": []interface{}{map[string]interface{}{"a/b": 1}},
+	})
+	out := root.Get("items", 0, "a/b")
+	if out.Root() != root {
+		t.Error("expected Root to return the root container")
+	}
+	if out.Parent().Parent().Parent() != root || root.Parent() != nil {
+		t.Error("unexpected parent chain")
+	}
+	if out.Key() != "a/b" || out.Parent().Key() != 0 || root.Key() != nil {
+		t.Error("unexpected keys")
+	}
+	if out.Depth() != 3 || root.Depth() != 0 {
+		t.Errorf("unexpected depth %d", out.Depth())
+	}
+	if !reflect.DeepEqual(out.Path(), []interface{}{"items", 0, "a/b"}) {
+		t.Errorf("unexpected path %v", out.Path())
+	}
+	if root.Get(out.Path()...).Data() != 1 {
+		t.Error("expected path to lead to the same value")
+	}
+	if out.PathString() != "items[0].a/b" {
+		t.Errorf("unexpected path string %q", out.PathString())
+	}
+	if out.Pointer() != "/items/0/a~1b" {
+		t.Errorf("unexpected pointer %q", out.Pointer())
+	}
+}
+
+func TestIntrospectionOnNilContainer(t *testing.T) {
+	var container *Container
+	if container.Parent() != nil || container.Root() != nil || container.Key() != nil {
+		t.Error("expected nil results")
+	}
+	if container.Depth() != 0 || len(container.Path()) != 0 {
+		t.Error("expected empty path")
+	}
+}
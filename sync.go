@@ -0,0 +1,146 @@
+package solenodon
+
+import "sync"
+
+// SyncContainer is a Container that is safe for concurrent use.
+// A SyncContainer returned by Get shares the lock of its root and follows its path again on every call.
+// Data returns a deep copy, and the Containers passed to View and Update must not be retained.
+type SyncContainer struct {
+	root *syncRoot
+	path []interface{}
+}
+
+type syncRoot struct {
+	mu        sync.RWMutex
+	container *Container
+}
+
+// NewSyncContainer returns a new SyncContainer for the given data.
+// The data must not be modified other than through the SyncContainer afterwards.
+func NewSyncContainer(data interface{}) *SyncContainer {
+	return &SyncContainer{root: &syncRoot{container: NewContainer(data)}}
+}
+
+// resolve returns the Container at the path of the SyncContainer, or nil if it no longer exists.
+// The lock must be held.
+func (s *SyncContainer) resolve() *Container {
+	return s.root.container.Get(s.path...)
+}
+
+// derive returns a SyncContainer for the given Container, which must have been resolved from the root.
+func (s *SyncContainer) derive(c *Container) *SyncContainer {
+	if c == nil {
+		return nil
+	}
+	return &SyncContainer{root: s.root, path: c.Path()}
+}
+
+// Get returns a SyncContainer for the value following the path of the given keys,
+// or nil if no value was found.
+func (s *SyncContainer) Get(keys ...interface{}) *SyncContainer {
+	if s == nil {
+		return nil
+	}
+	s.root.mu.RLock()
+	defer s.root.mu.RUnlock()
+	return s.derive(s.resolve().Get(keys...))
+}
+
+// Has returns true if the SyncContainer has a value for the given keys.
+func (s *SyncContainer) Has(keys ...interface{}) bool {
+	return s.Get(keys...) != nil
+}
+
+// Data returns a deep copy of the data in the SyncContainer, see Clone.
+func (s *SyncContainer) Data() interface{} {
+	if s == nil {
+		return nil
+	}
+	s.root.mu.RLock()
+	defer s.root.mu.RUnlock()
+	return s.resolve().Clone().Data()
+}
+
+// Path returns the keys that lead from the root to this SyncContainer.
+func (s *SyncContainer) Path() []interface{} {
+	if s == nil {
+		return nil
+	}
+	return append([]interface{}{}, s.path...)
+}
+
+// SetData sets the given data in the SyncContainer, see Container.SetData.
+// The SyncContainer on which this method is called will be returned, or nil if the data could not be set.
+func (s *SyncContainer) SetData(data interface{}) *SyncContainer {
+	if s == nil {
+		return nil
+	}
+	s.root.mu.Lock()
+	defer s.root.mu.Unlock()
+	if s.resolve().SetData(data) == nil {
+		return nil
+	}
+	return s
+}
+
+// Set sets the given data at the end of the path of the given keys, see Container.Set.
+// The SyncContainer at the end of the path will be returned, or nil if the data could not be set.
+func (s *SyncContainer) Set(data interface{}, keys ...interface{}) *SyncContainer {
+	if s == nil {
+		return nil
+	}
+	s.root.mu.Lock()
+	defer s.root.mu.Unlock()
+	return s.derive(s.resolve().Set(data, keys...))
+}
+
+// Delete the value, if any, at the end of the path of the given keys.
+// The SyncContainer on which this method is called will be returned.
+func (s *SyncContainer) Delete(keys ...interface{}) *SyncContainer {
+	if s == nil {
+		return nil
+	}
+	s.root.mu.Lock()
+	defer s.root.mu.Unlock()
+	s.resolve().Delete(keys...)
+	return s
+}
+
+// View calls fn with the Container at the path of the SyncContainer while holding a read lock.
+// fn must not modify the data, nor call methods of SyncContainers that share its lock, which would deadlock.
+// The error of fn is returned, or ErrNotFound if the value no longer exists.
+func (s *SyncContainer) View(fn func(c *Container) error) error {
+	if s == nil {
+		return ErrNotFound
+	}
+	s.root.mu.RLock()
+	defer s.root.mu.RUnlock()
+	c := s.resolve()
+	if c == nil {
+		return ErrNotFound
+	}
+	return fn(c)
+}
+
+// Update calls fn with a deep copy of the data in the SyncContainer while holding a write lock.
+// The copy replaces the data if fn returns nil; otherwise the error of fn is returned.
+// fn must not call methods of SyncContainers that share its lock, which would deadlock.
+func (s *SyncContainer) Update(fn func(tx *Container) error) error {
+	if s == nil {
+		return ErrNotFound
+	}
+	s.root.mu.Lock()
+	defer s.root.mu.Unlock()
+	c := s.resolve()
+	if c == nil {
+		return ErrNotFound
+	}
+	tx := c.Clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if c.SetData(tx.data) == nil {
+		return ErrNotFound
+	}
+	return nil
+}
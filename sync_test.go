@@ -0,0 +1,115 @@
+package solenodon
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSyncContainer(t *testing.T) {
+	s := NewSyncContainer(newJSONContainer(t, `{"database":{"server":"127.0.0.1","ports":[8080]}}`).Data())
+	database := s.Get("database")
+	if database == nil || !reflect.DeepEqual(database.Path(), []interface{}{"database"}) {
+		t.Fatalf("unexpected database %v", database)
+	}
+	server := database.Get("server")
+	s.Get("database", "server").SetData("10.0.0.1")
+	if data := server.Data(); data != "10.0.0.1" {
+		t.Errorf("expected derived container to observe write, got %v", data)
+	}
+	if database.Set(8081, "ports", Index(-1)) == nil {
+		t.Error("expected Set to succeed")
+	}
+	ports := database.Get("ports")
+	ports.Data().([]interface{})[0] = "changed"
+	if data := ports.Data(); !reflect.DeepEqual(data, []interface{}{8081}) {
+		t.Errorf("expected Data to return a copy, got %v", data)
+	}
+	s.Delete("database", "server")
+	if server.Data() != nil || server.SetData("x") != nil || server.Has() {
+		t.Error("expected deleted container to be empty")
+	}
+	if err := server.View(func(c *Container) error { return nil }); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	var nilSync *SyncContainer
+	if nilSync.Get("a") != nil || nilSync.Data() != nil || nilSync.Update(nil) != ErrNotFound {
+		t.Error("expected nil SyncContainer to be safe")
+	}
+}
+
+func TestSyncContainerUpdate(t *testing.T) {
+	s := NewSyncContainer(map[string]interface{}{"a": 1, "b": 2})
+	fail := errors.New("fail")
+	err := s.Update(func(tx *Container) error {
+		tx.Get("a").SetData(10)
+		return fail
+	})
+	if err != fail {
+		t.Errorf("expected error of fn, got %v", err)
+	}
+	if data := s.Data(); !reflect.DeepEqual(data, map[string]interface{}{"a": 1, "b": 2}) {
+		t.Errorf("expected failed update to leave data unchanged, got %v", data)
+	}
+	err = s.Update(func(tx *Container) error {
+		tx.Get("a").SetData(10)
+		tx.Delete("b")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := s.Data(); !reflect.DeepEqual(data, map[string]interface{}{"a": 10}) {
+		t.Errorf("unexpected data after update %v", data)
+	}
+}
+
+func TestSyncContainerConcurrent(t *testing.T) {
+	s := NewSyncContainer(map[string]interface{}{"counter": 0, "items": []interface{}{}})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Update(func(tx *Container) error {
+					n, _ := tx.Get("counter").Int()
+					tx.Get("counter").SetData(n + 1)
+					tx.Get("items").Append(j)
+					return nil
+				})
+				s.View(func(c *Container) error {
+					_ = c.Get("items").Len()
+					return nil
+				})
+				s.Get("counter").Data()
+			}
+		}()
+	}
+	wg.Wait()
+	if data := s.Get("counter").Data(); data != 800 {
+		t.Errorf("expected 800, got %v", data)
+	}
+	if n := len(s.Get("items").Data().([]interface{})); n != 800 {
+		t.Errorf("expected 800 items, got %d", n)
+	}
+}
+
+func TestSyncContainerGoValues(t *testing.T) {
+	s := NewSyncContainer(map[string]interface{}{"tags": []string{"a", "b"}})
+	err := s.Update(func(tx *Container) error {
+		tx.Get("tags", 0).SetData("MUTATED")
+		return errors.New("abort")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if data := s.Get("tags").Data(); !reflect.DeepEqual(data, []string{"a", "b"}) {
+		t.Errorf("expected failed update to leave Go values unchanged, got %v", data)
+	}
+	s.Get("tags").Data().([]string)[0] = "leaked"
+	if data := s.Get("tags", 0).Data(); data != "a" {
+		t.Errorf("expected Data to return a copy of Go values, got %v", data)
+	}
+}
@@ -0,0 +1,51 @@
+package solenodon
+
+import "errors"
+
+// SkipChildren can be returned by the function passed to Walk to skip the children of the current Container.
+var SkipChildren = errors.New("solenodon: skip children")
+
+// Stop can be returned by the function passed to Walk to stop walking. Walk will then return nil.
+var Stop = errors.New("solenodon: stop walk")
+
+// Walk calls fn for this Container and all of its descendants, depth-first, parents before children.
+// If fn returns SkipChildren, the children of the current Container are not visited.
+// If fn returns Stop, walking stops and Walk returns nil. Any other error stops walking and is returned by Walk.
+// A Go value that contains itself through a pointer or map is not visited again inside itself.
+func (c *Container) Walk(fn func(c *Container) error) error {
+	if c == nil {
+		return nil
+	}
+	if err := c.walk(fn, map[pointerKey]bool{}); err != nil && err != Stop {
+		return err
+	}
+	return nil
+}
+
+// walk visits c and its descendants. The ancestors are the pointers and maps that are being visited.
+func (c *Container) walk(fn func(c *Container) error, ancestors map[pointerKey]bool) error {
+	if key, ok := pointerOf(c.data); ok {
+		if ancestors[key] {
+			return nil
+		}
+		ancestors[key] = true
+		defer delete(ancestors, key)
+	}
+	if err := fn(c); err != nil {
+		if err == SkipChildren {
+			return nil
+		}
+		return err
+	}
+	for _, key := range c.Keys() {
+		child := c.Get(key)
+		if child == nil {
+			// The child was deleted by fn.
+			continue
+		}
+		if err := child.walk(fn, ancestors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
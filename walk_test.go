@@ -0,0 +1,78 @@
+package solenodon
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWalk(t *testing.T) {
+	container, err := NewContainerFromBytes([]byte("b: [1, {c: 2}]\na: x\n3: y\n"), yaml.Unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	err = container.Walk(func(c *Container) error {
+		paths = append(paths, c.PathString())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"", "[3]", "a", "b", "b[0]", "b[1]", "b[1].c"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestWalkSkipChildrenAndStop(t *testing.T) {
+	container := newJSONContainer(t, `{"a":{"b":1},"c":[1,2],"d":3}`)
+	var paths []string
+	err := container.Walk(func(c *Container) error {
+		paths = append(paths, c.PathString())
+		switch c.PathString() {
+		case "a":
+			return SkipChildren
+		case "c[0]":
+			return Stop
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"", "a", "c", "c[0]"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestWalkError(t *testing.T) {
+	container := newJSONContainer(t, `{"a":{"b":1}}`)
+	walkErr := errors.New("foo")
+	err := container.Walk(func(c *Container) error {
+		if c.Depth() == 2 {
+			return walkErr
+		}
+		return nil
+	})
+	if err != walkErr {
+		t.Errorf("expected %v, got %v", walkErr, err)
+	}
+}
+
+func TestWalkModifies(t *testing.T) {
+	container := newJSONContainer(t, `{"a":{"secret":"x","b":[{"secret":"y"}]},"secret":"z"}`)
+	container.Walk(func(c *Container) error {
+		if c.Key() == "secret" {
+			c.SetData("***")
+		}
+		return nil
+	})
+	expected := newJSONContainer(t, `{"a":{"secret":"***","b":[{"secret":"***"}]},"secret":"***"}`)
+	if !reflect.DeepEqual(container.Data(), expected.Data()) {
+		t.Errorf("expected %v, got %v", expected.Data(), container.Data())
+	}
+}